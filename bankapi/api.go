@@ -4,186 +4,127 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"github.com/gin-gonic/gin"
 	"net/http"
-	"os"
 	"strconv"
-	"sync"
 	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	db "github.com/chawino/bank-api/db/sqlc"
+	"github.com/chawino/bank-api/import/ofx"
+	"github.com/chawino/bank-api/money"
+	"github.com/chawino/bank-api/token"
+	"github.com/chawino/bank-api/util"
+
 	_ "github.com/lib/pq"
 )
 
 var ErrNotFound = errors.New("user: not found")
 
-type User struct {
-	mu        sync.Mutex
-	ID        int64     `json:"id"`
-	FirstName string    `json:"first_name" binding:"required"`
-	LastName  string    `json:"last_name" binding:"required"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
-}
-
-type BankAccount struct {
-	mu            sync.Mutex
-	ID            int64     `json:"id"`
-	UserID        int64     `json:"user_id"`
-	AccountNumber string    `json:"account_number" binding:"required"`
-	Name          string    `json:"name"`
-	Balance       int       `json:"balance"`
-	CreatedAt     time.Time `json:"createdAt"`
-	UpdatedAt     time.Time `json:"updatedAt"`
-}
-
-type Secret struct {
-	ID  int64  `json:"id"`
-	Key string `json:"key" binding:"required"`
-}
-
 type Server struct {
-	db                 *sql.DB
-	userService        UserService
-	bankAccountService BankAccountService
-	transferService    TransferService
-	secretService      SecretService
-}
-
-type UserService interface {
-	All() ([]User, error)
-	Insert(user *User) error
-	InsertBankAccount(bankAccount *BankAccount) error
-	GetByID(id int) (*User, error)
-	GetBankAccountsByUserID(user int) ([]BankAccount, error)
-	Update(id int, first_name string, last_name string) (*User, error)
-	DeleteByID(id int) error
-}
-
-type BankAccountService interface {
-	Deposit(bankAccountId int, balance int) (*BankAccount, error)
-	Withdraw(bankAccountId int, balance int) (*BankAccount, error)
-	DeleteAccountByBankAccountID(bankAccountId int) error
+	store               db.Store
+	tokenMaker          token.Maker
+	accessTokenDuration time.Duration
 }
 
-type TransferService interface {
-	Transfer(from string, to string, amount int) error
+// NewServer builds a Server backed by store. Handlers depend only on the
+// db.Store interface so they can be unit-tested against a mock store.
+func NewServer(store db.Store, tokenMaker token.Maker, accessTokenDuration time.Duration) *Server {
+	return &Server{
+		store:               store,
+		tokenMaker:          tokenMaker,
+		accessTokenDuration: accessTokenDuration,
+	}
 }
 
-type SecretService interface {
-	Insert(s *Secret) error
+// userResponse is what a User looks like over the wire: no hashed_password.
+type userResponse struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
-type SecretServiceImp struct {
-	db *sql.DB
+func newUserResponse(user db.User) userResponse {
+	return userResponse{
+		ID:        user.ID,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		CreatedAt: user.CreatedAt,
+	}
 }
 
-func (s *Server) CreateSecret(c *gin.Context) {
-	var secret Secret
-	if err := c.ShouldBindJSON(&secret); err != nil {
+func (s *Server) Register(c *gin.Context) {
+	var h struct {
+		Username  string `json:"username" binding:"required"`
+		Password  string `json:"password" binding:"required,min=8"`
+		FirstName string `json:"first_name" binding:"required"`
+		LastName  string `json:"last_name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&h); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, err)
 		return
 	}
-	if err := s.secretService.Insert(&secret); err != nil {
+
+	hashedPassword, err := util.HashPassword(h.Password)
+	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusCreated, secret)
-}
-
-func (s *SecretServiceImp) Insert(secret *Secret) error {
-	row := s.db.QueryRow("INSERT INTO secrets (key) values ($1) RETURNING id", secret.Key)
 
-	if err := row.Scan(&secret.ID); err != nil {
-		return err
+	user, err := s.store.CreateUser(c.Request.Context(), db.CreateUserParams{
+		Username:       h.Username,
+		HashedPassword: hashedPassword,
+		FirstName:      h.FirstName,
+		LastName:       h.LastName,
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
 	}
-	return nil
-}
-
-type UserServiceImp struct {
-	mu sync.Mutex
-	db *sql.DB
-}
-
-type BankAccountServiceImp struct {
-	mu sync.Mutex
-	db *sql.DB
-}
 
-type TransferServiceImp struct {
-	mu sync.Mutex
-	db *sql.DB
+	c.JSON(http.StatusCreated, newUserResponse(user))
 }
 
-func (s *UserServiceImp) All() ([]User, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	rows, err := s.db.Query("SELECT * FROM users")
-	if err != nil {
-		return nil, err
+func (s *Server) Login(c *gin.Context) {
+	var h struct {
+		Username string `json:"username" binding:"required"`
+		Password string `json:"password" binding:"required"`
 	}
-	users := []User{} // set empty slice without nil
-	for rows.Next() {
-		var user User
-		err := rows.Scan(&user.ID, &user.FirstName, &user.LastName, &user.UpdatedAt, &user.CreatedAt)
-		if err != nil {
-			return nil, err
-		}
-		users = append(users, user)
-	}
-	return users, nil
-}
-
-func (s *UserServiceImp) Insert(user *User) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	now := time.Now()
-	user.CreatedAt = now
-	user.UpdatedAt = now
-	row := s.db.QueryRow("INSERT INTO users (first_name, last_name, created_at, updated_at) values ($1, $2, $3, $4) RETURNING id", user.FirstName, user.LastName, now, now)
-
-	if err := row.Scan(&user.ID); err != nil {
-		return err
+	if err := c.ShouldBindJSON(&h); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, err)
+		return
 	}
-	return nil
-}
 
-func (s *UserServiceImp) GetByID(id int) (*User, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	stmt := "SELECT * FROM users WHERE id = $1"
-	row := s.db.QueryRow(stmt, id)
-	var user User
-	err := row.Scan(&user.ID, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	ctx := c.Request.Context()
+	user, err := s.store.GetUserByUsername(ctx, h.Username)
 	if err != nil {
-		return nil, err
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid username or password"})
+		return
 	}
-	return &user, nil
-}
-
-func (s *UserServiceImp) Update(id int, fisrt_name string, last_name string) (*User, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	stmt := "UPDATE users SET first_name = $2, last_name = $3 WHERE id = $1"
-	_, err := s.db.Exec(stmt, id, fisrt_name, last_name)
-	if err != nil {
-		return nil, err
+	if err := util.CheckPassword(h.Password, user.HashedPassword); err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid username or password"})
+		return
 	}
-	return s.GetByID(id)
-}
 
-func (s *UserServiceImp) DeleteByID(id int) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	stmt := "DELETE FROM users WHERE id = $1"
-	_, err := s.db.Exec(stmt, id)
+	accessToken, payload, err := s.tokenMaker.CreateToken(user.ID, s.accessTokenDuration)
 	if err != nil {
-		return err
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
 	}
-	return nil
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token": accessToken,
+		"expires_at":   payload.ExpiredAt,
+		"user":         newUserResponse(user),
+	})
 }
 
 func (s *Server) All(c *gin.Context) {
-	todos, err := s.userService.All()
+	users, err := s.store.ListUsers(c.Request.Context())
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"object":  "error",
@@ -191,31 +132,12 @@ func (s *Server) All(c *gin.Context) {
 		})
 		return
 	}
-	c.JSON(http.StatusOK, todos)
-}
-
-func (s *Server) Create(c *gin.Context) {
-	var user User
-	err := c.ShouldBindJSON(&user)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"object":  "error",
-			"message": fmt.Sprintf("json: wrong params: %s", err),
-		})
-		return
-	}
-
-	if err := s.userService.Insert(&user); err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
-		return
-	}
-
-	c.JSON(http.StatusCreated, user)
+	c.JSON(http.StatusOK, users)
 }
 
 func (s *Server) GetByID(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
-	user, err := s.userService.GetByID(id)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	user, err := s.store.GetUser(c.Request.Context(), id)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
 		return
@@ -229,18 +151,22 @@ func (s *Server) Update(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, err)
 		return
 	}
-	id, _ := strconv.Atoi(c.Param("id"))
-	todo, err := s.userService.Update(id, h["first_name"], h["last_name"])
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	user, err := s.store.UpdateUser(c.Request.Context(), db.UpdateUserParams{
+		ID:        id,
+		FirstName: h["first_name"],
+		LastName:  h["last_name"],
+	})
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, todo)
+	c.JSON(http.StatusOK, user)
 }
 
 func (s *Server) DeleteByID(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
-	if err := s.userService.DeleteByID(id); err != nil {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := s.store.DeleteUser(c.Request.Context(), id); err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
 		return
 	}
@@ -248,190 +174,359 @@ func (s *Server) DeleteByID(c *gin.Context) {
 
 // ####### BANK ACCOUNT #########
 
-func (s *UserServiceImp) InsertBankAccount(bankAccount *BankAccount) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	// check user_id exist
-	user, err := s.GetByID(int(bankAccount.UserID))
+func (s *Server) CreateBankAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+	userId, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	var h struct {
+		AccountNumber   string `json:"account_number" binding:"required"`
+		Currency        string `json:"currency" binding:"required"`
+		Type            string `json:"type"`
+		ParentAccountID *int64 `json:"parent_account_id"`
+	}
+	if err := c.ShouldBindJSON(&h); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"object":  "error",
+			"message": fmt.Sprintf("json: wrong params: %s", err),
+		})
+		return
+	}
+	if !money.ValidCurrency(h.Currency) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"object":  "error",
+			"message": fmt.Sprintf("currency: invalid ISO 4217 code %q", h.Currency),
+		})
+		return
+	}
+	if h.Type == "" {
+		h.Type = db.AccountTypeBank
+	} else if !db.ValidAccountType(h.Type) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"object":  "error",
+			"message": fmt.Sprintf("type: unknown account type %q", h.Type),
+		})
+		return
+	}
+
+	user, err := s.store.GetUser(ctx, userId)
 	if err != nil {
-		return err
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
 	}
 
-	// check account_number exist
-	now := time.Now()
-	bankAccount.CreatedAt = now
-	bankAccount.UpdatedAt = now
-	bankAccount.Balance = 0
-	row := s.db.QueryRow("INSERT INTO bank_accounts (user_id, account_number, account_name, balance, created_at, updated_at) values ($1, $2, $3, $4, $5, $6) RETURNING id", bankAccount.UserID, bankAccount.AccountNumber, user.FirstName+user.LastName, bankAccount.Balance, now, now)
+	var parentAccountID sql.NullInt64
+	if h.ParentAccountID != nil {
+		parent, err := s.store.GetBankAccount(ctx, *h.ParentAccountID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+			return
+		}
+		if parent.UserID != userId {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"object":  "error",
+				"message": "parent_account_id: parent account belongs to a different user",
+			})
+			return
+		}
+		parentAccountID = sql.NullInt64{Int64: *h.ParentAccountID, Valid: true}
+	}
 
-	if err := row.Scan(&bankAccount.ID); err != nil {
-		return err
+	bankAccount, err := s.store.CreateBankAccount(ctx, db.CreateBankAccountParams{
+		UserID:          userId,
+		AccountNumber:   h.AccountNumber,
+		Name:            user.FirstName + user.LastName,
+		Currency:        h.Currency,
+		Type:            h.Type,
+		ParentAccountID: parentAccountID,
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
 	}
-	return nil
+
+	c.JSON(http.StatusCreated, bankAccount)
 }
 
-func (s *UserServiceImp) GetBankAccountsByUserID(id int) ([]BankAccount, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	fmt.Println("GetBankAccountsByUserId " + strconv.Itoa(id))
-	rows, err := s.db.Query("SELECT * FROM bank_accounts WHERE user_id = $1", id)
+func (s *Server) GetBankAccountsByUserID(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	bankAccounts, err := s.store.ListBankAccountsByUserID(c.Request.Context(), id)
 	if err != nil {
-		return nil, err
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, bankAccounts)
+}
+
+// accountTreeNode is a BankAccount with its descendants nested under it and
+// its balance aggregated with theirs, for the chart-of-accounts tree view.
+type accountTreeNode struct {
+	db.BankAccount
+	AggregateBalance money.Money        `json:"aggregate_balance"`
+	Children         []*accountTreeNode `json:"children"`
+}
+
+func buildAccountTree(accounts []db.BankAccount) []*accountTreeNode {
+	byParent := map[int64][]db.BankAccount{}
+	var roots []db.BankAccount
+	for _, a := range accounts {
+		if a.ParentAccountID.Valid {
+			byParent[a.ParentAccountID.Int64] = append(byParent[a.ParentAccountID.Int64], a)
+		} else {
+			roots = append(roots, a)
+		}
 	}
 
-	//fmt.Println("GetBankAccountsByUserId rows size" + strconv.Itoa(len(rows)))
-	bankAccounts := []BankAccount{} // set empty slice without nil
-	for rows.Next() {
-		var bankAccount BankAccount
-		fmt.Println("GetBankAccountsByUserId rows size" + bankAccount.AccountNumber)
-		err := rows.Scan(&bankAccount.ID, &bankAccount.UserID, &bankAccount.AccountNumber, &bankAccount.Name, &bankAccount.Balance, &bankAccount.UpdatedAt, &bankAccount.CreatedAt)
-		if err != nil {
-			fmt.Println("GetBankAccountsByUserId error" + err.Error())
-			return nil, err
+	var buildNode func(a db.BankAccount) *accountTreeNode
+	buildNode = func(a db.BankAccount) *accountTreeNode {
+		node := &accountTreeNode{BankAccount: a, AggregateBalance: a.Balance}
+		for _, child := range byParent[a.ID] {
+			childNode := buildNode(child)
+			node.Children = append(node.Children, childNode)
+			node.AggregateBalance = node.AggregateBalance.Add(childNode.AggregateBalance)
 		}
-		bankAccounts = append(bankAccounts, bankAccount)
+		return node
+	}
+
+	nodes := make([]*accountTreeNode, 0, len(roots))
+	for _, root := range roots {
+		nodes = append(nodes, buildNode(root))
 	}
-	return bankAccounts, nil
+	return nodes
 }
 
-func (s *Server) CreateBankAccount(c *gin.Context) {
-	userId, _ := strconv.Atoi(c.Param("id"))
-	var bankAccount BankAccount
-	err := c.ShouldBindJSON(&bankAccount)
+func (s *Server) GetAccountsTree(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	accounts, err := s.store.ListBankAccountsByUserID(c.Request.Context(), id)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"object":  "error",
-			"message": fmt.Sprintf("json: wrong params: %s", err),
-		})
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
 		return
 	}
+	c.JSON(http.StatusOK, buildAccountTree(accounts))
+}
 
-	bankAccount.UserID = int64(userId)
+func (s *Server) SetBankAccountParent(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
 
-	if err := s.userService.InsertBankAccount(&bankAccount); err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+	var h struct {
+		ParentAccountID *int64 `json:"parent_account_id"`
+	}
+	if err := c.ShouldBindJSON(&h); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, bankAccount)
-}
-
-func (s *Server) GetBankAccountsByUserID(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
-	bankAccounts, err := s.userService.GetBankAccountsByUserID(id)
+	account, err := s.store.SetBankAccountParentTx(c.Request.Context(), db.SetBankAccountParentTxParams{
+		AccountID:       id,
+		ParentAccountID: h.ParentAccountID,
+	})
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, bankAccounts)
+	c.JSON(http.StatusOK, account)
 }
 
 func (s *Server) DeleteAccountByBankAccountID(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
-	if err := s.bankAccountService.DeleteAccountByBankAccountID(id); err != nil {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := s.store.DeleteBankAccount(c.Request.Context(), id); err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
 		return
 	}
 }
 
-func (s *BankAccountServiceImp) DeleteAccountByBankAccountID(id int) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	stmt := "DELETE FROM bank_accounts WHERE id = $1"
-	_, err := s.db.Exec(stmt, id)
+func (s *Server) DepositByID(c *gin.Context) {
+	h := struct {
+		Amount string `json:"amount" binding:"required"`
+	}{}
+	if err := c.ShouldBindJSON(&h); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, err)
+		return
+	}
+	amount, err := money.Parse(h.Amount)
+	if err != nil || !amount.IsPositive() {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "amount must be a positive decimal"})
+		return
+	}
+
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	result, err := s.store.DepositTx(c.Request.Context(), db.DepositTxParams{
+		AccountID: id,
+		Amount:    amount,
+	})
 	if err != nil {
-		return err
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
 	}
-	return nil
+	c.JSON(http.StatusOK, result.Account)
 }
 
-func (s *Server) DepositByID(c *gin.Context) {
-	h := map[string]int{}
+func (s *Server) WithdrawByID(c *gin.Context) {
+	h := struct {
+		Amount string `json:"amount" binding:"required"`
+	}{}
 	if err := c.ShouldBindJSON(&h); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, err)
 		return
 	}
-	id, _ := strconv.Atoi(c.Param("id"))
-	todo, err := s.bankAccountService.Deposit(id, h["amount"])
+	amount, err := money.Parse(h.Amount)
+	if err != nil || !amount.IsPositive() {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "amount must be a positive decimal"})
+		return
+	}
+
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	result, err := s.store.WithdrawTx(c.Request.Context(), db.WithdrawTxParams{
+		AccountID: id,
+		Amount:    amount,
+	})
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, todo)
+	c.JSON(http.StatusOK, result.Account)
 }
 
-func (s *BankAccountServiceImp) Deposit(id int, amount int) (*BankAccount, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	stmt := "SELECT * FROM bank_accounts WHERE id = $1"
-	row := s.db.QueryRow(stmt, id)
-	var bankAccount BankAccount
-	err := row.Scan(&bankAccount.ID, &bankAccount.UserID, &bankAccount.AccountNumber, &bankAccount.Name, &bankAccount.Balance, &bankAccount.CreatedAt, &bankAccount.UpdatedAt)
+func (s *Server) GetTransactionsByBankAccountID(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	transactions, err := s.store.ListTransactionsByBankAccountID(c.Request.Context(), id)
 	if err != nil {
-		return nil, err
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
 	}
+	c.JSON(http.StatusOK, transactions)
+}
+
+// #### OFX IMPORT ####
+
+// SetBankAccountOFXConfig stores the connection details (server URL, FI
+// org/fid, user, bank/account ids) that ImportOFXFetch needs to pull an
+// account's statements on demand. The login password isn't persisted; it's
+// supplied per-fetch instead.
+func (s *Server) SetBankAccountOFXConfig(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
 
-	balance := bankAccount.Balance
-	b := balance + amount
-	bankAccount.Balance = b
+	var h struct {
+		OFXURL    string `json:"ofx_url" binding:"required"`
+		OFXOrg    string `json:"ofx_org" binding:"required"`
+		OFXFID    string `json:"ofx_fid" binding:"required"`
+		OFXUser   string `json:"ofx_user" binding:"required"`
+		OFXBankID string `json:"ofx_bank_id" binding:"required"`
+		OFXAcctID string `json:"ofx_acct_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&h); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, err)
+		return
+	}
+	if err := ofx.ValidateServerURL(h.OFXURL); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	stmt = "UPDATE bank_accounts SET balance = $2 WHERE id = $1"
-	_, err = s.db.Exec(stmt, id, b)
+	account, err := s.store.UpdateBankAccountOFXConfig(c.Request.Context(), db.UpdateBankAccountOFXConfigParams{
+		ID:        id,
+		OFXURL:    sql.NullString{String: h.OFXURL, Valid: true},
+		OFXOrg:    sql.NullString{String: h.OFXOrg, Valid: true},
+		OFXFID:    sql.NullString{String: h.OFXFID, Valid: true},
+		OFXUser:   sql.NullString{String: h.OFXUser, Valid: true},
+		OFXBankID: sql.NullString{String: h.OFXBankID, Valid: true},
+		OFXAcctID: sql.NullString{String: h.OFXAcctID, Valid: true},
+	})
 	if err != nil {
-		return nil, err
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
 	}
+	c.JSON(http.StatusOK, account)
+}
 
-	return &bankAccount, nil
+func ofxEntriesFrom(transactions []ofx.Transaction) []db.ImportOFXEntry {
+	entries := make([]db.ImportOFXEntry, len(transactions))
+	for i, t := range transactions {
+		memo := t.Memo
+		if memo == "" {
+			memo = t.Name
+		}
+		entries[i] = db.ImportOFXEntry{RemoteID: t.FITID, Posted: t.Posted, Amount: t.Amount, Memo: memo}
+	}
+	return entries
 }
 
-func (s *Server) GetBankAccountByBankAccountId(id int) (*BankAccount, error) {
-	stmt := "SELECT id, user_id, amount FROM bank_accounts WHERE id = $1"
-	row := s.db.QueryRow(stmt, id)
-	var bankAccount BankAccount
-	err := row.Scan(&bankAccount.ID, &bankAccount.UserID, &bankAccount.Balance)
+// ImportOFXFile parses an uploaded OFX/QFX statement and merges its
+// <STMTTRN> entries into the account's ledger, skipping any FITID already
+// imported.
+func (s *Server) ImportOFXFile(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	defer file.Close()
+
+	transactions, err := ofx.Parse(file)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := s.store.ImportOFXTx(c.Request.Context(), db.ImportOFXTxParams{
+		AccountID: id,
+		Entries:   ofxEntriesFrom(transactions),
+	})
 	if err != nil {
-		return nil, err
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
 	}
-	return &bankAccount, nil
+	c.JSON(http.StatusOK, result)
 }
 
-func (s *Server) WithdrawByID(c *gin.Context) {
-	h := map[string]int{}
+// ImportOFXFetch performs the OFX HTTP dialog against the account's
+// configured OFX server and merges any new transactions into the ledger.
+func (s *Server) ImportOFXFetch(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	var h struct {
+		Password string `json:"password" binding:"required"`
+	}
 	if err := c.ShouldBindJSON(&h); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, err)
 		return
 	}
-	id, _ := strconv.Atoi(c.Param("id"))
-	todo, err := s.bankAccountService.Withdraw(id, h["amount"])
+
+	ctx := c.Request.Context()
+	account, err := s.store.GetBankAccount(ctx, id)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, todo)
-}
+	if !account.OFXURL.Valid {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "account has no OFX connection configured"})
+		return
+	}
 
-func (s *BankAccountServiceImp) Withdraw(id int, amount int) (*BankAccount, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	stmt := "SELECT * FROM bank_accounts WHERE id = $1"
-	row := s.db.QueryRow(stmt, id)
-	var bankAccount BankAccount
-	err := row.Scan(&bankAccount.ID, &bankAccount.UserID, &bankAccount.AccountNumber, &bankAccount.Name, &bankAccount.Balance, &bankAccount.CreatedAt, &bankAccount.UpdatedAt)
+	transactions, err := ofx.Fetch(ctx, nil, ofx.ClientConfig{
+		URL:      account.OFXURL.String,
+		Org:      account.OFXOrg.String,
+		FID:      account.OFXFID.String,
+		User:     account.OFXUser.String,
+		Password: h.Password,
+		BankID:   account.OFXBankID.String,
+		AcctID:   account.OFXAcctID.String,
+	})
 	if err != nil {
-		return nil, err
+		c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
 	}
 
-	balance := bankAccount.Balance
-	b := balance - amount
-	bankAccount.Balance = b
-
-	stmt = "UPDATE bank_accounts SET balance = $2 WHERE id = $1"
-	_, err = s.db.Exec(stmt, id, b)
+	result, err := s.store.ImportOFXTx(ctx, db.ImportOFXTxParams{
+		AccountID: id,
+		Entries:   ofxEntriesFrom(transactions),
+	})
 	if err != nil {
-		return nil, err
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
 	}
-
-	return &bankAccount, nil
+	c.JSON(http.StatusOK, result)
 }
 
 // #### TRANSFER Service ####
@@ -440,13 +535,20 @@ func (s *Server) Transfer(c *gin.Context) {
 	h := struct {
 		From   string `json:"from"`
 		To     string `json:"to"`
-		Amount int    `json:"amount"`
+		Amount string `json:"amount"`
 	}{}
 	if err := c.ShouldBindJSON(&h); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, err)
 		return
 	}
-	err := s.transferService.Transfer(h.From, h.To, h.Amount)
+	amount, err := money.Parse(h.Amount)
+	if err != nil || !amount.IsPositive() {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "amount must be a positive decimal"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	fromAccount, err := s.store.GetBankAccountByAccountNumber(ctx, h.From)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
@@ -454,169 +556,114 @@ func (s *Server) Transfer(c *gin.Context) {
 		})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "success",
-		"message":  "transferred",
-	})
-}
-
-func (s *TransferServiceImp) Transfer(from string, to string, amount int) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	// query from account
-	stmt := "SELECT * FROM bank_accounts WHERE account_number = $1"
-	row := s.db.QueryRow(stmt, from)
-	var fromAccount BankAccount
-	err := row.Scan(&fromAccount.ID, &fromAccount.UserID, &fromAccount.AccountNumber, &fromAccount.Name, &fromAccount.Balance, &fromAccount.CreatedAt, &fromAccount.UpdatedAt)
-	if err != nil {
-		return err
-	}
-
-	// query to account
-	stmt = "SELECT * FROM bank_accounts WHERE account_number = $1"
-	row = s.db.QueryRow(stmt, to)
-	var toAccount BankAccount
-	err = row.Scan(&toAccount.ID, &toAccount.UserID, &toAccount.AccountNumber, &toAccount.Name, &toAccount.Balance, &toAccount.CreatedAt, &toAccount.UpdatedAt)
-	if err != nil {
-		return err
-	}
-
-	// check balance from account
-	balanceFrom := fromAccount.Balance
-	if balanceFrom < amount {
-		return errors.New("Balance less than amount")
+	if fromAccount.UserID != authPayload(c).UserID {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "from account doesn't belong to the authenticated user",
+		})
+		return
 	}
-
-	// update balance from account before add amount to receiver
-	fromAccount.Balance = balanceFrom - amount
-	now := time.Now()
-	fromAccount.CreatedAt = now
-	fromAccount.UpdatedAt = now
-
-	stmt = "UPDATE bank_accounts SET balance = $2 WHERE account_number = $1"
-	_, err = s.db.Exec(stmt, from, fromAccount.Balance)
+	toAccount, err := s.store.GetBankAccountByAccountNumber(ctx, h.To)
 	if err != nil {
-		return err
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": fmt.Sprintf("%s", err),
+		})
+		return
 	}
 
-	// update balance to account after ... amount to receiver
-	toAccount.Balance = toAccount.Balance + amount
-	now = time.Now()
-	toAccount.CreatedAt = now
-	toAccount.UpdatedAt = now
-
-	stmt = "UPDATE bank_accounts SET balance = $2 WHERE account_number = $1"
-	_, err = s.db.Exec(stmt, to, toAccount.Balance)
+	_, err = s.store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        amount,
+	})
 	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (s *Server) AuthTodo(c *gin.Context) {
-	user, _, ok := c.Request.BasicAuth()
-	if ok {
-		row := s.db.QueryRow("SELECT key FROM secrets WHERE key = $1", user)
-		if err := row.Scan(&user); err == nil {
-			return
-		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": fmt.Sprintf("%s", err),
+		})
+		return
 	}
-	c.AbortWithStatus(http.StatusUnauthorized)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "transferred",
+	})
 }
 
 func setupRoute(s *Server) *gin.Engine {
 	r := gin.New()
 	r.Use(RequestLogger())
+	r.Use(Metrics())
+	r.GET("/metrics", metricsHandler()) // unauthenticated, scraped by Prometheus over a trusted network
+
 	users := r.Group("/users")
+	users.POST("/register", s.Register)
+	users.POST("/login", s.Login)
+
+	usersAuth := users.Group("/")
+	usersAuth.Use(AuthMiddleware(s.tokenMaker))
+	usersAuth.GET("/", s.All)
+	usersAuth.GET("/:id", s.requireSelf, s.GetByID)
+	usersAuth.PUT("/:id", s.requireSelf, s.Update)
+	usersAuth.DELETE("/:id", s.requireSelf, s.DeleteByID)
+	usersAuth.POST("/:id/bankAccount", s.requireSelf, s.CreateBankAccount)
+	usersAuth.GET("/:id/bankAccount", s.requireSelf, s.GetBankAccountsByUserID)
+	usersAuth.GET("/:id/accounts/tree", s.requireSelf, s.GetAccountsTree)
+
 	bankAccounts := r.Group("/bankAccounts")
+	bankAccounts.Use(AuthMiddleware(s.tokenMaker))
+	bankAccounts.DELETE("/:id", s.requireAccountOwner, s.DeleteAccountByBankAccountID)
+	bankAccounts.PUT("/:id/parent", s.requireAccountOwner, s.SetBankAccountParent)
+	bankAccounts.PUT("/:id/withdraw", s.requireAccountOwner, s.WithdrawByID)
+	bankAccounts.PUT("/:id/deposit", s.requireAccountOwner, s.DepositByID)
+	bankAccounts.GET("/:id/transactions", s.requireAccountOwner, s.GetTransactionsByBankAccountID)
+	bankAccounts.PUT("/:id/ofx", s.requireAccountOwner, s.SetBankAccountOFXConfig)
+	bankAccounts.POST("/:id/import/ofx", s.requireAccountOwner, s.ImportOFXFile)
+	bankAccounts.POST("/:id/import/ofx/fetch", s.requireAccountOwner, s.ImportOFXFetch)
+
 	transfers := r.Group("/transfers")
-	admin := r.Group("/admin")
-
-	admin.Use(gin.BasicAuth(gin.Accounts{
-		"admin": "1234",
-	}))
-	users.Use(s.AuthTodo)
-	users.GET("/", s.All)
-	users.POST("/", s.Create)
-	users.GET("/:id", s.GetByID)
-	users.PUT("/:id", s.Update)
-	users.DELETE("/:id", s.DeleteByID)
-
-	users.POST("/:id/bankAccount", s.CreateBankAccount)
-	users.GET("/:id/bankAccount", s.GetBankAccountsByUserID)
-
-	bankAccounts.Use(s.AuthTodo)
-	bankAccounts.DELETE("/:id", s.DeleteAccountByBankAccountID)
-	bankAccounts.PUT("/:id/withdraw", s.WithdrawByID)
-	bankAccounts.PUT("/:id/deposit", s.DepositByID)
-
-	transfers.Use(s.AuthTodo)
+	transfers.Use(AuthMiddleware(s.tokenMaker))
 	transfers.POST("/", s.Transfer)
 
-	admin.POST("/secrets", s.CreateSecret)
-
 	return r
 }
 
-func RequestLogger() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		fmt.Println("####### Print request body #######") // Print request body
-		fmt.Println(c.Request)
-		fmt.Println("####### END Print request body #######") // Print request body
+func newTokenMaker(config util.Config) (token.Maker, error) {
+	switch config.TokenMaker {
+	case util.TokenMakerPaseto:
+		return token.NewPasetoMaker(config.TokenSymmetricKey)
+	default:
+		return token.NewJWTMaker(config.TokenSymmetricKey)
 	}
 }
 
 func StartServer() {
-	db, err := sql.Open("postgres", os.Getenv("DATABASE_URL"))
+	config := util.LoadConfig()
+
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	if level, err := logrus.ParseLevel(config.LogLevel); err == nil {
+		logrus.SetLevel(level)
+	}
+
+	conn, err := sql.Open("postgres", config.DatabaseURL)
 	if err != nil {
 		return
 	}
-	createTable := `
-	CREATE TABLE IF NOT EXISTS users (
-		id SERIAL PRIMARY KEY,
-		first_name TEXT,
-		last_name TEXT,
-		created_at TIMESTAMP WITHOUT TIME ZONE,
-		updated_at TIMESTAMP WITHOUT TIME ZONE
-	);
-	CREATE TABLE IF NOT EXISTS bank_accounts (
-		id SERIAL PRIMARY KEY,
-		user_id INTEGER,
-		account_number TEXT UNIQUE,
-		account_name TEXT,
-		balance INTEGER,
-		created_at TIMESTAMP WITHOUT TIME ZONE,
-		updated_at TIMESTAMP WITHOUT TIME ZONE
-	);
-	CREATE TABLE IF NOT EXISTS secrets (
-		id SERIAL PRIMARY KEY,
-		key TEXT
-	);
-	`
-
-	if _, err := db.Exec(createTable); err != nil {
+
+	if err := db.AutoMigrate(conn); err != nil {
 		fmt.Printf("%s", err)
 		return
 	}
 
-	s := &Server{
-		db: db,
-		userService: &UserServiceImp{
-			db: db,
-		},
-		bankAccountService: &BankAccountServiceImp{
-			db: db,
-		},
-		transferService: &TransferServiceImp{
-			db: db,
-		},
-		secretService: &SecretServiceImp{
-			db: db,
-		},
+	tokenMaker, err := newTokenMaker(config)
+	if err != nil {
+		fmt.Printf("%s", err)
+		return
 	}
 
+	s := NewServer(db.NewStore(conn), tokenMaker, config.AccessTokenDuration)
+
 	r := setupRoute(s)
 
-	r.Run(":" + os.Getenv("PORT"))
+	r.Run(":" + config.Port)
 }
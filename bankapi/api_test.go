@@ -0,0 +1,294 @@
+package bankapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	mockdb "github.com/chawino/bank-api/db/mock"
+	db "github.com/chawino/bank-api/db/sqlc"
+	"github.com/chawino/bank-api/money"
+	"github.com/chawino/bank-api/token"
+	"github.com/chawino/bank-api/util"
+)
+
+const testSecretKey = "01234567890123456789012345678901"
+
+func newTestServer(t *testing.T, store db.Store) (*gin.Engine, token.Maker) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	tokenMaker, err := token.NewJWTMaker(testSecretKey)
+	if err != nil {
+		t.Fatalf("failed to build test token maker: %v", err)
+	}
+
+	server := NewServer(store, tokenMaker, time.Minute)
+	return setupRoute(server), tokenMaker
+}
+
+func addAuthorization(t *testing.T, req *http.Request, tokenMaker token.Maker, userID int64) {
+	t.Helper()
+	accessToken, _, err := tokenMaker.CreateToken(userID, time.Minute)
+	if err != nil {
+		t.Fatalf("failed to create test token: %v", err)
+	}
+	req.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+}
+
+func TestServer_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hashedPassword, err := util.HashPassword("s3cret123")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUserByUsername(gomock.Any(), "jane").
+		Times(1).
+		Return(db.User{ID: 1, Username: "jane", HashedPassword: hashedPassword}, nil)
+
+	router, _ := newTestServer(t, store)
+
+	body, _ := json.Marshal(gin.H{"username": "jane", "password": "s3cret123"})
+	req := httptest.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(body))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestServer_RequestLogger_EchoesRequestID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	router, _ := newTestServer(t, store)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/register", bytes.NewReader(nil))
+	req.Header.Set(requestIDHeaderKey, "test-request-id")
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	if got := recorder.Header().Get(requestIDHeaderKey); got != "test-request-id" {
+		t.Fatalf("expected X-Request-ID to be echoed back, got %q", got)
+	}
+}
+
+func TestServer_RequestLogger_DoesNotTruncateBody(t *testing.T) {
+	logrus.SetLevel(logrus.DebugLevel)
+	defer logrus.SetLevel(logrus.InfoLevel)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Padded well past maxLoggedBodyBytes so truncation would corrupt the JSON.
+	firstName := strings.Repeat("a", maxLoggedBodyBytes+1024)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		CreateUser(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateUserParams) (db.User, error) {
+			if len(arg.FirstName) != len(firstName) {
+				t.Fatalf("expected first_name of length %d, got %d", len(firstName), len(arg.FirstName))
+			}
+			return db.User{ID: 1, FirstName: arg.FirstName}, nil
+		})
+
+	router, _ := newTestServer(t, store)
+
+	body, _ := json.Marshal(gin.H{
+		"username":   "alice",
+		"password":   "supersecret",
+		"first_name": firstName,
+		"last_name":  "Doe",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/users/register", bytes.NewReader(body))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	router, _ := newTestServer(t, store)
+
+	warmupReq := httptest.NewRequest(http.MethodPost, "/users/register", bytes.NewReader(nil))
+	router.ServeHTTP(httptest.NewRecorder(), warmupReq)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if !bytes.Contains(recorder.Body.Bytes(), []byte("http_requests_total")) {
+		t.Fatalf("expected /metrics to expose http_requests_total, got: %s", recorder.Body.String())
+	}
+}
+
+func TestServer_GetByID_RejectsOtherUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	router, tokenMaker := newTestServer(t, store)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/2", nil)
+	addAuthorization(t, req, tokenMaker, 1)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestServer_DepositByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetBankAccount(gomock.Any(), int64(1)).
+		Times(1).
+		Return(db.BankAccount{ID: 1, UserID: 7}, nil)
+	store.EXPECT().
+		DepositTx(gomock.Any(), db.DepositTxParams{AccountID: 1, Amount: money.MustParse("500")}).
+		Times(1).
+		Return(db.DepositTxResult{Account: db.BankAccount{ID: 1, UserID: 7, Balance: money.MustParse("1500")}}, nil)
+
+	router, tokenMaker := newTestServer(t, store)
+
+	body, _ := json.Marshal(gin.H{"amount": "500"})
+	req := httptest.NewRequest(http.MethodPut, "/bankAccounts/1/deposit", bytes.NewReader(body))
+	addAuthorization(t, req, tokenMaker, 7)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	var got db.BankAccount
+	if err := json.Unmarshal(recorder.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if got.Balance.Cmp(money.MustParse("1500")) != 0 {
+		t.Fatalf("expected balance 1500, got %s", got.Balance)
+	}
+}
+
+func TestServer_DepositByID_RejectsNonOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetBankAccount(gomock.Any(), int64(1)).
+		Times(1).
+		Return(db.BankAccount{ID: 1, UserID: 7}, nil)
+
+	router, tokenMaker := newTestServer(t, store)
+
+	body, _ := json.Marshal(gin.H{"amount": "500"})
+	req := httptest.NewRequest(http.MethodPut, "/bankAccounts/1/deposit", bytes.NewReader(body))
+	addAuthorization(t, req, tokenMaker, 99)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestServer_CreateBankAccount_RejectsCrossTenantParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUser(gomock.Any(), int64(7)).
+		Times(1).
+		Return(db.User{ID: 7}, nil)
+	store.EXPECT().
+		GetBankAccount(gomock.Any(), int64(1)).
+		Times(1).
+		Return(db.BankAccount{ID: 1, UserID: 99}, nil)
+
+	router, tokenMaker := newTestServer(t, store)
+
+	parentID := int64(1)
+	body, _ := json.Marshal(gin.H{"account_number": "acc-7", "currency": "USD", "parent_account_id": parentID})
+	req := httptest.NewRequest(http.MethodPost, "/users/7/bankAccount", bytes.NewReader(body))
+	addAuthorization(t, req, tokenMaker, 7)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestServer_Transfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetBankAccountByAccountNumber(gomock.Any(), "acc-1").
+		Times(1).
+		Return(db.BankAccount{ID: 1, UserID: 7, AccountNumber: "acc-1"}, nil)
+	store.EXPECT().
+		GetBankAccountByAccountNumber(gomock.Any(), "acc-2").
+		Times(1).
+		Return(db.BankAccount{ID: 2, UserID: 8, AccountNumber: "acc-2"}, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), db.TransferTxParams{FromAccountID: 1, ToAccountID: 2, Amount: money.MustParse("100")}).
+		Times(1).
+		Return(db.TransferTxResult{}, nil)
+
+	router, tokenMaker := newTestServer(t, store)
+
+	body, _ := json.Marshal(gin.H{"from": "acc-1", "to": "acc-2", "amount": "100"})
+	req := httptest.NewRequest(http.MethodPost, "/transfers/", bytes.NewReader(body))
+	addAuthorization(t, req, tokenMaker, 7)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+}
@@ -0,0 +1,83 @@
+package bankapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/chawino/bank-api/token"
+)
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+	authorizationPayloadKey = "authPayload"
+)
+
+// AuthMiddleware verifies the Authorization: Bearer <token> header and
+// stores the resulting token.Payload in the gin context under
+// authorizationPayloadKey.
+func AuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authorizationHeader := c.GetHeader(authorizationHeaderKey)
+		if len(authorizationHeader) == 0 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header is not provided"})
+			return
+		}
+
+		fields := strings.Fields(authorizationHeader)
+		if len(fields) < 2 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+			return
+		}
+
+		if strings.ToLower(fields[0]) != authorizationTypeBearer {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("unsupported authorization type %s", fields[0])})
+			return
+		}
+
+		payload, err := tokenMaker.VerifyToken(fields[1])
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set(authorizationPayloadKey, payload)
+		c.Next()
+	}
+}
+
+func authPayload(c *gin.Context) *token.Payload {
+	return c.MustGet(authorizationPayloadKey).(*token.Payload)
+}
+
+// requireSelf enforces that the :id path param matches the authenticated
+// user, for the /users/:id/* routes.
+func (s *Server) requireSelf(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if authPayload(c).UserID != id {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "user account doesn't belong to the authenticated user"})
+		return
+	}
+	c.Next()
+}
+
+// requireAccountOwner enforces that the bank account named by the :id path
+// param belongs to the authenticated user, for the /bankAccounts/:id/*
+// routes.
+func (s *Server) requireAccountOwner(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	account, err := s.store.GetBankAccount(c.Request.Context(), id)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
+	}
+	if account.UserID != authPayload(c).UserID {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "bank account doesn't belong to the authenticated user"})
+		return
+	}
+	c.Next()
+}
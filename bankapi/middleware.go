@@ -0,0 +1,157 @@
+package bankapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+const requestIDHeaderKey = "X-Request-ID"
+const requestIDContextKey = "requestID"
+
+// maxLoggedBodyBytes caps how much of a request body RequestLogger puts in
+// the "body" log field when debug logging is on. The full body is always
+// restored to c.Request.Body for the handler; only the logged copy is
+// truncated.
+const maxLoggedBodyBytes = 1 << 20 // 1 MiB
+
+// RequestID returns the request ID RequestLogger assigned to c, echoed to
+// the client via the X-Request-ID response header.
+func RequestID(c *gin.Context) string {
+	id, _ := c.Get(requestIDContextKey)
+	requestID, _ := id.(string)
+	return requestID
+}
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests handled, labeled by method, path and status.",
+	}, []string{"method", "path", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, labeled by method, path and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "path", "status"})
+)
+
+// sensitiveBodyFields holds the JSON keys redacted from any logged request
+// body, matched case-insensitively.
+var sensitiveBodyFields = map[string]bool{
+	"password":        true,
+	"hashed_password": true,
+	"token":           true,
+	"access_token":    true,
+	"refresh_token":   true,
+	"secret":          true,
+	"secret_key":      true,
+	"ofx_user":        true,
+}
+
+// redactBody parses body as JSON and replaces any sensitiveBodyFields value
+// with "[REDACTED]". Bodies that aren't a JSON object (file uploads,
+// empty bodies) are reported by shape only, never by content.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "<non-json body omitted>"
+	}
+	for key := range fields {
+		if sensitiveBodyFields[strings.ToLower(key)] {
+			fields[key] = "[REDACTED]"
+		}
+	}
+	redacted, err := json.Marshal(fields)
+	if err != nil {
+		return "<body omitted>"
+	}
+	return string(redacted)
+}
+
+// RequestLogger emits one structured (JSON) log line per request via
+// logrus, carrying the method, path, status, latency, client IP and a
+// request ID. The request ID is read from X-Request-ID when the caller
+// supplied one, otherwise generated, and always echoed back on the
+// response so callers can correlate it with their own logs.
+//
+// Request bodies are only read and logged when logrus is at debug level,
+// and sensitive fields (passwords, tokens, secret keys) are redacted
+// before they're logged.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeaderKey)
+		if requestID == "" {
+			if id, err := uuid.NewRandom(); err == nil {
+				requestID = id.String()
+			} else {
+				requestID = strconv.FormatInt(time.Now().UnixNano(), 36)
+			}
+		}
+		c.Set(requestIDContextKey, requestID)
+		c.Header(requestIDHeaderKey, requestID)
+
+		fields := logrus.Fields{
+			"request_id": requestID,
+			"method":     c.Request.Method,
+			"client_ip":  c.ClientIP(),
+		}
+		if logrus.IsLevelEnabled(logrus.DebugLevel) {
+			body, err := io.ReadAll(c.Request.Body)
+			if err == nil {
+				c.Request.Body = io.NopCloser(bytes.NewReader(body))
+				loggedBody := body
+				if len(loggedBody) > maxLoggedBodyBytes {
+					loggedBody = loggedBody[:maxLoggedBodyBytes]
+				}
+				fields["body"] = redactBody(loggedBody)
+			}
+		}
+
+		start := time.Now()
+		c.Next()
+
+		fields["path"] = c.FullPath()
+		fields["status"] = c.Writer.Status()
+		fields["latency_ms"] = time.Since(start).Milliseconds()
+		logrus.WithFields(fields).Info("handled request")
+	}
+}
+
+// Metrics records http_requests_total and http_request_duration_seconds
+// for every request, labeled by method, route path (the matched gin
+// pattern, e.g. "/users/:id") and status.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		httpRequestsTotal.WithLabelValues(c.Request.Method, path, status).Inc()
+		httpRequestDuration.WithLabelValues(c.Request.Method, path, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// metricsHandler exposes the default Prometheus registry for scraping.
+func metricsHandler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
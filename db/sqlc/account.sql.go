@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/chawino/bank-api/money"
+)
+
+const bankAccountCols = "id, user_id, account_number, account_name, balance, currency, account_type, parent_account_id, ofx_url, ofx_org, ofx_fid, ofx_user, ofx_bank_id, ofx_acct_id, created_at, updated_at"
+
+const createBankAccount = `-- name: CreateBankAccount :one
+INSERT INTO bank_accounts (user_id, account_number, account_name, balance, currency, account_type, parent_account_id, created_at, updated_at)
+VALUES ($1, $2, $3, 0, $4, $5, $6, $7, $8)
+RETURNING ` + bankAccountCols
+
+type CreateBankAccountParams struct {
+	UserID          int64         `json:"user_id"`
+	AccountNumber   string        `json:"account_number"`
+	Name            string        `json:"name"`
+	Currency        string        `json:"currency"`
+	Type            string        `json:"type"`
+	ParentAccountID sql.NullInt64 `json:"parent_account_id"`
+}
+
+func (q *Queries) CreateBankAccount(ctx context.Context, arg CreateBankAccountParams) (BankAccount, error) {
+	now := time.Now()
+	row := q.db.QueryRowContext(ctx, createBankAccount, arg.UserID, arg.AccountNumber, arg.Name, arg.Currency, arg.Type, arg.ParentAccountID, now, now)
+	var i BankAccount
+	err := row.Scan(&i.ID, &i.UserID, &i.AccountNumber, &i.Name, &i.Balance, &i.Currency, &i.Type, &i.ParentAccountID,
+		&i.OFXURL, &i.OFXOrg, &i.OFXFID, &i.OFXUser, &i.OFXBankID, &i.OFXAcctID, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const getBankAccount = `-- name: GetBankAccount :one
+SELECT ` + bankAccountCols + ` FROM bank_accounts WHERE id = $1
+`
+
+func (q *Queries) GetBankAccount(ctx context.Context, id int64) (BankAccount, error) {
+	row := q.db.QueryRowContext(ctx, getBankAccount, id)
+	var i BankAccount
+	err := row.Scan(&i.ID, &i.UserID, &i.AccountNumber, &i.Name, &i.Balance, &i.Currency, &i.Type, &i.ParentAccountID,
+		&i.OFXURL, &i.OFXOrg, &i.OFXFID, &i.OFXUser, &i.OFXBankID, &i.OFXAcctID, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const getBankAccountByAccountNumber = `-- name: GetBankAccountByAccountNumber :one
+SELECT ` + bankAccountCols + ` FROM bank_accounts WHERE account_number = $1
+`
+
+func (q *Queries) GetBankAccountByAccountNumber(ctx context.Context, accountNumber string) (BankAccount, error) {
+	row := q.db.QueryRowContext(ctx, getBankAccountByAccountNumber, accountNumber)
+	var i BankAccount
+	err := row.Scan(&i.ID, &i.UserID, &i.AccountNumber, &i.Name, &i.Balance, &i.Currency, &i.Type, &i.ParentAccountID,
+		&i.OFXURL, &i.OFXOrg, &i.OFXFID, &i.OFXUser, &i.OFXBankID, &i.OFXAcctID, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const getBankAccountForUpdate = `-- name: GetBankAccountForUpdate :one
+SELECT ` + bankAccountCols + ` FROM bank_accounts WHERE id = $1 FOR UPDATE
+`
+
+// GetBankAccountForUpdate takes a row-level lock so the caller's
+// read-modify-write of balance can't race with a concurrent transaction
+// touching the same account. Only valid when q wraps a *sql.Tx.
+func (q *Queries) GetBankAccountForUpdate(ctx context.Context, id int64) (BankAccount, error) {
+	row := q.db.QueryRowContext(ctx, getBankAccountForUpdate, id)
+	var i BankAccount
+	err := row.Scan(&i.ID, &i.UserID, &i.AccountNumber, &i.Name, &i.Balance, &i.Currency, &i.Type, &i.ParentAccountID,
+		&i.OFXURL, &i.OFXOrg, &i.OFXFID, &i.OFXUser, &i.OFXBankID, &i.OFXAcctID, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const listBankAccountsByUserID = `-- name: ListBankAccountsByUserID :many
+SELECT ` + bankAccountCols + ` FROM bank_accounts WHERE user_id = $1
+`
+
+func (q *Queries) ListBankAccountsByUserID(ctx context.Context, userID int64) ([]BankAccount, error) {
+	rows, err := q.db.QueryContext(ctx, listBankAccountsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bankAccounts := []BankAccount{}
+	for rows.Next() {
+		var i BankAccount
+		if err := rows.Scan(&i.ID, &i.UserID, &i.AccountNumber, &i.Name, &i.Balance, &i.Currency, &i.Type, &i.ParentAccountID,
+			&i.OFXURL, &i.OFXOrg, &i.OFXFID, &i.OFXUser, &i.OFXBankID, &i.OFXAcctID, &i.CreatedAt, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		bankAccounts = append(bankAccounts, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return bankAccounts, nil
+}
+
+const updateBankAccountBalance = `-- name: UpdateBankAccountBalance :one
+UPDATE bank_accounts SET balance = $2, updated_at = $3 WHERE id = $1
+RETURNING ` + bankAccountCols
+
+type UpdateBankAccountBalanceParams struct {
+	ID      int64       `json:"id"`
+	Balance money.Money `json:"balance"`
+}
+
+func (q *Queries) UpdateBankAccountBalance(ctx context.Context, arg UpdateBankAccountBalanceParams) (BankAccount, error) {
+	row := q.db.QueryRowContext(ctx, updateBankAccountBalance, arg.ID, arg.Balance, time.Now())
+	var i BankAccount
+	err := row.Scan(&i.ID, &i.UserID, &i.AccountNumber, &i.Name, &i.Balance, &i.Currency, &i.Type, &i.ParentAccountID,
+		&i.OFXURL, &i.OFXOrg, &i.OFXFID, &i.OFXUser, &i.OFXBankID, &i.OFXAcctID, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const updateBankAccountParent = `-- name: UpdateBankAccountParent :one
+UPDATE bank_accounts SET parent_account_id = $2, updated_at = $3 WHERE id = $1
+RETURNING ` + bankAccountCols
+
+type UpdateBankAccountParentParams struct {
+	ID              int64         `json:"id"`
+	ParentAccountID sql.NullInt64 `json:"parent_account_id"`
+}
+
+func (q *Queries) UpdateBankAccountParent(ctx context.Context, arg UpdateBankAccountParentParams) (BankAccount, error) {
+	row := q.db.QueryRowContext(ctx, updateBankAccountParent, arg.ID, arg.ParentAccountID, time.Now())
+	var i BankAccount
+	err := row.Scan(&i.ID, &i.UserID, &i.AccountNumber, &i.Name, &i.Balance, &i.Currency, &i.Type, &i.ParentAccountID,
+		&i.OFXURL, &i.OFXOrg, &i.OFXFID, &i.OFXUser, &i.OFXBankID, &i.OFXAcctID, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const updateBankAccountOFXConfig = `-- name: UpdateBankAccountOFXConfig :one
+UPDATE bank_accounts SET ofx_url = $2, ofx_org = $3, ofx_fid = $4, ofx_user = $5, ofx_bank_id = $6, ofx_acct_id = $7, updated_at = $8
+WHERE id = $1
+RETURNING ` + bankAccountCols
+
+type UpdateBankAccountOFXConfigParams struct {
+	ID        int64          `json:"id"`
+	OFXURL    sql.NullString `json:"ofx_url"`
+	OFXOrg    sql.NullString `json:"ofx_org"`
+	OFXFID    sql.NullString `json:"ofx_fid"`
+	OFXUser   sql.NullString `json:"ofx_user"`
+	OFXBankID sql.NullString `json:"ofx_bank_id"`
+	OFXAcctID sql.NullString `json:"ofx_acct_id"`
+}
+
+func (q *Queries) UpdateBankAccountOFXConfig(ctx context.Context, arg UpdateBankAccountOFXConfigParams) (BankAccount, error) {
+	row := q.db.QueryRowContext(ctx, updateBankAccountOFXConfig, arg.ID, arg.OFXURL, arg.OFXOrg, arg.OFXFID, arg.OFXUser, arg.OFXBankID, arg.OFXAcctID, time.Now())
+	var i BankAccount
+	err := row.Scan(&i.ID, &i.UserID, &i.AccountNumber, &i.Name, &i.Balance, &i.Currency, &i.Type, &i.ParentAccountID,
+		&i.OFXURL, &i.OFXOrg, &i.OFXFID, &i.OFXUser, &i.OFXBankID, &i.OFXAcctID, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const deleteBankAccount = `-- name: DeleteBankAccount :exec
+DELETE FROM bank_accounts WHERE id = $1
+`
+
+func (q *Queries) DeleteBankAccount(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteBankAccount, id)
+	return err
+}
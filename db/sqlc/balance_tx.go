@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/chawino/bank-api/money"
+)
+
+// DepositTxParams contains the input parameters of a deposit transaction.
+type DepositTxParams struct {
+	AccountID int64       `json:"account_id"`
+	Amount    money.Money `json:"amount"`
+}
+
+// DepositTxResult is the result of a deposit transaction.
+type DepositTxResult struct {
+	Account     BankAccount `json:"account"`
+	Transaction Transaction `json:"transaction"`
+	Entry       Entry       `json:"entry"`
+}
+
+// DepositTx credits Amount to AccountID, recording it as a Transaction with a
+// matching Entry, all inside a single locked transaction.
+func (store *SQLStore) DepositTx(ctx context.Context, arg DepositTxParams) (DepositTxResult, error) {
+	var result DepositTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetBankAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.UpdateBankAccountBalance(ctx, UpdateBankAccountBalanceParams{
+			ID:      account.ID,
+			Balance: account.Balance.Add(arg.Amount),
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transaction, err = q.CreateTransaction(ctx, CreateTransactionParams{
+			ToAccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+			Amount:      arg.Amount,
+			Kind:        TransactionKindDeposit,
+			Status:      TransactionStatusCompleted,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			TransactionID: result.Transaction.ID,
+			AccountID:     account.ID,
+			Amount:        arg.Amount,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// WithdrawTxParams contains the input parameters of a withdraw transaction.
+type WithdrawTxParams struct {
+	AccountID int64       `json:"account_id"`
+	Amount    money.Money `json:"amount"`
+}
+
+// WithdrawTxResult is the result of a withdraw transaction.
+type WithdrawTxResult struct {
+	Account     BankAccount `json:"account"`
+	Transaction Transaction `json:"transaction"`
+	Entry       Entry       `json:"entry"`
+}
+
+// WithdrawTx debits Amount from AccountID, recording it as a Transaction
+// with a matching Entry, all inside a single locked transaction.
+func (store *SQLStore) WithdrawTx(ctx context.Context, arg WithdrawTxParams) (WithdrawTxResult, error) {
+	var result WithdrawTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetBankAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Balance.Cmp(arg.Amount) < 0 {
+			return errBalanceLessThanAmount
+		}
+
+		result.Account, err = q.UpdateBankAccountBalance(ctx, UpdateBankAccountBalanceParams{
+			ID:      account.ID,
+			Balance: account.Balance.Sub(arg.Amount),
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transaction, err = q.CreateTransaction(ctx, CreateTransactionParams{
+			FromAccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+			Amount:        arg.Amount,
+			Kind:          TransactionKindWithdraw,
+			Status:        TransactionStatusCompleted,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			TransactionID: result.Transaction.ID,
+			AccountID:     account.ID,
+			Amount:        arg.Amount.Neg(),
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,27 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DBTX is satisfied by both *sql.DB and *sql.Tx so the generated queries can
+// run standalone or inside a transaction started by SQLStore.execTx.
+type DBTX interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+func New(db DBTX) *Queries {
+	return &Queries{db: db}
+}
+
+type Queries struct {
+	db DBTX
+}
+
+func (q *Queries) WithTx(tx *sql.Tx) *Queries {
+	return &Queries{db: tx}
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/chawino/bank-api/money"
+)
+
+const createEntry = `-- name: CreateEntry :one
+INSERT INTO entries (transaction_id, account_id, amount, created_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, transaction_id, account_id, amount, created_at
+`
+
+type CreateEntryParams struct {
+	TransactionID int64       `json:"transaction_id"`
+	AccountID     int64       `json:"account_id"`
+	Amount        money.Money `json:"amount"`
+	// CreatedAt backdates the entry to match its transaction. Left zero,
+	// it defaults to time.Now().
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
+	createdAt := arg.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	row := q.db.QueryRowContext(ctx, createEntry, arg.TransactionID, arg.AccountID, arg.Amount, createdAt)
+	var i Entry
+	err := row.Scan(&i.ID, &i.TransactionID, &i.AccountID, &i.Amount, &i.CreatedAt)
+	return i, err
+}
@@ -0,0 +1,11 @@
+package db
+
+import "errors"
+
+var errBalanceLessThanAmount = errors.New("balance less than amount")
+
+var errCurrencyMismatch = errors.New("from and to accounts have different currencies")
+
+var errParentCycle = errors.New("account cannot be re-parented under its own descendant")
+
+var errCrossTenantParent = errors.New("parent account belongs to a different user")
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/chawino/bank-api/money"
+)
+
+// ImportOFXEntry is a single parsed statement line to merge into an
+// account's ledger, keyed by the OFX FITID (or equivalent remote id) the
+// statement assigned it.
+type ImportOFXEntry struct {
+	RemoteID string
+	Posted   time.Time
+	Amount   money.Money
+	Memo     string
+}
+
+// ImportOFXTxParams contains the input parameters of an OFX import.
+type ImportOFXTxParams struct {
+	AccountID int64
+	Entries   []ImportOFXEntry
+}
+
+// ImportOFXTxResult is the result of an OFX import.
+type ImportOFXTxResult struct {
+	Account  BankAccount   `json:"account"`
+	Imported []Transaction `json:"imported"`
+	Skipped  int           `json:"skipped"`
+}
+
+// ImportOFXTx merges Entries into AccountID's ledger, skipping any entry
+// whose RemoteID was already imported for this account (see the splits
+// table's unique (account_id, remote_id) index), all inside a single locked
+// transaction so the account balance always reflects exactly the imported
+// entries.
+func (store *SQLStore) ImportOFXTx(ctx context.Context, arg ImportOFXTxParams) (ImportOFXTxResult, error) {
+	var result ImportOFXTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetBankAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range arg.Entries {
+			exists, err := q.ExistsSplit(ctx, arg.AccountID, entry.RemoteID)
+			if err != nil {
+				return err
+			}
+			if exists {
+				result.Skipped++
+				continue
+			}
+
+			account.Balance = account.Balance.Add(entry.Amount)
+			account, err = q.UpdateBankAccountBalance(ctx, UpdateBankAccountBalanceParams{
+				ID:      account.ID,
+				Balance: account.Balance,
+			})
+			if err != nil {
+				return err
+			}
+
+			memo := sql.NullString{}
+			if entry.Memo != "" {
+				memo = sql.NullString{String: entry.Memo, Valid: true}
+			}
+			transaction, err := q.CreateTransaction(ctx, CreateTransactionParams{
+				ToAccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+				Amount:      entry.Amount,
+				Kind:        TransactionKindImport,
+				Status:      TransactionStatusCompleted,
+				Memo:        memo,
+				CreatedAt:   entry.Posted,
+			})
+			if err != nil {
+				return err
+			}
+
+			ledgerEntry, err := q.CreateEntry(ctx, CreateEntryParams{
+				TransactionID: transaction.ID,
+				AccountID:     account.ID,
+				Amount:        entry.Amount,
+				CreatedAt:     entry.Posted,
+			})
+			if err != nil {
+				return err
+			}
+
+			if _, err := q.CreateSplit(ctx, CreateSplitParams{
+				AccountID: account.ID,
+				RemoteID:  entry.RemoteID,
+				EntryID:   ledgerEntry.ID,
+			}); err != nil {
+				return err
+			}
+
+			result.Imported = append(result.Imported, transaction)
+		}
+
+		result.Account = account
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/chawino/bank-api/money"
+)
+
+func mustMoney(s string) money.Money {
+	m, err := money.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func fixedTime() time.Time {
+	t, _ := time.Parse(time.RFC3339, "2024-01-15T00:00:00Z")
+	return t
+}
+
+func TestSQLStore_ImportOFXTx_SkipsDuplicateFITID(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM bank_accounts WHERE id = \\$1 FOR UPDATE").
+		WithArgs(int64(1)).
+		WillReturnRows(bankAccountRow(1, nil, "USD"))
+
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM splits WHERE account_id = \$1 AND remote_id = \$2\)`).
+		WithArgs(int64(1), "dup-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM splits WHERE account_id = \$1 AND remote_id = \$2\)`).
+		WithArgs(int64(1), "new-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	mock.ExpectQuery(`UPDATE bank_accounts SET balance`).
+		WithArgs(int64(1), "10.0000", sqlmock.AnyArg()).
+		WillReturnRows(bankAccountRow(1, nil, "USD"))
+
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WithArgs(nil, int64(1), "10.0000", TransactionKindImport, TransactionStatusCompleted, nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "from_account_id", "to_account_id", "amount", "kind", "status", "memo", "created_at"}).
+			AddRow(int64(100), nil, int64(1), "10.0000", TransactionKindImport, TransactionStatusCompleted, nil, fixedTime()))
+
+	mock.ExpectQuery(`INSERT INTO entries`).
+		WithArgs(int64(100), int64(1), "10.0000", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "account_id", "amount", "created_at"}).
+			AddRow(int64(200), int64(100), int64(1), "10.0000", fixedTime()))
+
+	mock.ExpectQuery(`INSERT INTO splits`).
+		WithArgs(int64(1), "new-1", int64(200), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "account_id", "remote_id", "entry_id", "created_at"}).
+			AddRow(int64(300), int64(1), "new-1", int64(200), fixedTime()))
+
+	mock.ExpectCommit()
+
+	result, err := store.ImportOFXTx(context.Background(), ImportOFXTxParams{
+		AccountID: 1,
+		Entries: []ImportOFXEntry{
+			{RemoteID: "dup-1", Amount: mustMoney("5")},
+			{RemoteID: "new-1", Amount: mustMoney("10")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Skipped != 1 {
+		t.Fatalf("expected 1 skipped entry, got %d", result.Skipped)
+	}
+	if len(result.Imported) != 1 {
+		t.Fatalf("expected 1 imported transaction, got %d", len(result.Imported))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package db
+
+import "database/sql"
+
+// AutoMigrate creates the tables the application needs if they don't exist
+// yet. It stands in for a proper migration tool (e.g. golang-migrate) until
+// one is wired up.
+func AutoMigrate(conn *sql.DB) error {
+	const schema = `
+	CREATE TABLE IF NOT EXISTS users (
+		id SERIAL PRIMARY KEY,
+		username TEXT UNIQUE,
+		hashed_password TEXT,
+		first_name TEXT,
+		last_name TEXT,
+		created_at TIMESTAMP WITHOUT TIME ZONE,
+		updated_at TIMESTAMP WITHOUT TIME ZONE
+	);
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS username TEXT UNIQUE;
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS hashed_password TEXT;
+	CREATE TABLE IF NOT EXISTS bank_accounts (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER,
+		account_number TEXT UNIQUE,
+		account_name TEXT,
+		balance NUMERIC(20,4),
+		currency TEXT NOT NULL DEFAULT 'USD',
+		account_type TEXT NOT NULL DEFAULT 'bank',
+		parent_account_id INTEGER REFERENCES bank_accounts(id),
+		created_at TIMESTAMP WITHOUT TIME ZONE,
+		updated_at TIMESTAMP WITHOUT TIME ZONE
+	);
+	ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'USD';
+	ALTER TABLE bank_accounts ALTER COLUMN balance TYPE NUMERIC(20,4);
+	ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS account_type TEXT NOT NULL DEFAULT 'bank';
+	ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS parent_account_id INTEGER REFERENCES bank_accounts(id);
+	ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS ofx_url TEXT;
+	ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS ofx_org TEXT;
+	ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS ofx_fid TEXT;
+	ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS ofx_user TEXT;
+	ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS ofx_bank_id TEXT;
+	ALTER TABLE bank_accounts ADD COLUMN IF NOT EXISTS ofx_acct_id TEXT;
+	CREATE TABLE IF NOT EXISTS transactions (
+		id SERIAL PRIMARY KEY,
+		from_account_id INTEGER REFERENCES bank_accounts(id),
+		to_account_id INTEGER REFERENCES bank_accounts(id),
+		amount NUMERIC(20,4) NOT NULL,
+		kind TEXT NOT NULL,
+		status TEXT NOT NULL,
+		memo TEXT,
+		created_at TIMESTAMP WITHOUT TIME ZONE
+	);
+	ALTER TABLE transactions ALTER COLUMN amount TYPE NUMERIC(20,4);
+	ALTER TABLE transactions ADD COLUMN IF NOT EXISTS memo TEXT;
+	CREATE TABLE IF NOT EXISTS entries (
+		id SERIAL PRIMARY KEY,
+		transaction_id INTEGER REFERENCES transactions(id),
+		account_id INTEGER REFERENCES bank_accounts(id),
+		amount NUMERIC(20,4) NOT NULL,
+		created_at TIMESTAMP WITHOUT TIME ZONE
+	);
+	ALTER TABLE entries ALTER COLUMN amount TYPE NUMERIC(20,4);
+	CREATE TABLE IF NOT EXISTS splits (
+		id SERIAL PRIMARY KEY,
+		account_id INTEGER REFERENCES bank_accounts(id),
+		remote_id TEXT NOT NULL,
+		entry_id INTEGER REFERENCES entries(id),
+		created_at TIMESTAMP WITHOUT TIME ZONE
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS splits_account_id_remote_id_idx ON splits (account_id, remote_id);
+	`
+	_, err := conn.Exec(schema)
+	return err
+}
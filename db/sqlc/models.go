@@ -0,0 +1,114 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/chawino/bank-api/money"
+)
+
+type User struct {
+	ID             int64     `json:"id"`
+	Username       string    `json:"username"`
+	HashedPassword string    `json:"-"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+type BankAccount struct {
+	ID              int64          `json:"id"`
+	UserID          int64          `json:"user_id"`
+	AccountNumber   string         `json:"account_number"`
+	Name            string         `json:"name"`
+	Balance         money.Money    `json:"balance"`
+	Currency        string         `json:"currency"`
+	Type            string         `json:"type"`
+	ParentAccountID sql.NullInt64  `json:"parent_account_id"`
+	OFXURL          sql.NullString `json:"ofx_url,omitempty"`
+	OFXOrg          sql.NullString `json:"ofx_org,omitempty"`
+	OFXFID          sql.NullString `json:"ofx_fid,omitempty"`
+	OFXUser         sql.NullString `json:"ofx_user,omitempty"`
+	OFXBankID       sql.NullString `json:"ofx_bank_id,omitempty"`
+	OFXAcctID       sql.NullString `json:"ofx_acct_id,omitempty"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+}
+
+// Account types form the chart-of-accounts taxonomy a BankAccount can
+// belong to. They're free-form TEXT in the database (not a Postgres enum)
+// so new types don't require a migration, same as TransactionKind below.
+const (
+	AccountTypeBank       = "bank"
+	AccountTypeCash       = "cash"
+	AccountTypeAsset      = "asset"
+	AccountTypeLiability  = "liability"
+	AccountTypeInvestment = "investment"
+	AccountTypeIncome     = "income"
+	AccountTypeExpense    = "expense"
+	AccountTypeEquity     = "equity"
+	AccountTypeReceivable = "receivable"
+	AccountTypePayable    = "payable"
+)
+
+var accountTypes = map[string]bool{
+	AccountTypeBank:       true,
+	AccountTypeCash:       true,
+	AccountTypeAsset:      true,
+	AccountTypeLiability:  true,
+	AccountTypeInvestment: true,
+	AccountTypeIncome:     true,
+	AccountTypeExpense:    true,
+	AccountTypeEquity:     true,
+	AccountTypeReceivable: true,
+	AccountTypePayable:    true,
+}
+
+// ValidAccountType reports whether t is one of the known account types.
+func ValidAccountType(t string) bool {
+	return accountTypes[t]
+}
+
+// Transaction is an immutable ledger record of a single money movement
+// (deposit, withdrawal, transfer or OFX import). Per-account effects are recorded as
+// Entries so a balance can always be reconstructed/audited from history.
+type Transaction struct {
+	ID            int64          `json:"id"`
+	FromAccountID sql.NullInt64  `json:"from_account_id"`
+	ToAccountID   sql.NullInt64  `json:"to_account_id"`
+	Amount        money.Money    `json:"amount"`
+	Kind          string         `json:"kind"`
+	Status        string         `json:"status"`
+	Memo          sql.NullString `json:"memo,omitempty"`
+	CreatedAt     time.Time      `json:"createdAt"`
+}
+
+// Entry records the signed delta a Transaction applied to a single account.
+type Entry struct {
+	ID            int64       `json:"id"`
+	TransactionID int64       `json:"transaction_id"`
+	AccountID     int64       `json:"account_id"`
+	Amount        money.Money `json:"amount"`
+	CreatedAt     time.Time   `json:"createdAt"`
+}
+
+// Split records the external reference (e.g. an OFX FITID) an imported
+// Entry was created from, so the same statement line is never imported
+// twice for the same account.
+type Split struct {
+	ID        int64     `json:"id"`
+	AccountID int64     `json:"account_id"`
+	RemoteID  string    `json:"remote_id"`
+	EntryID   int64     `json:"entry_id"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+const (
+	TransactionKindDeposit  = "deposit"
+	TransactionKindWithdraw = "withdraw"
+	TransactionKindTransfer = "transfer"
+	TransactionKindImport   = "import"
+
+	TransactionStatusCompleted = "completed"
+)
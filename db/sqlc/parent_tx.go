@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SetBankAccountParentTxParams contains the input parameters of a
+// re-parenting transaction. A nil ParentAccountID detaches the account,
+// making it a root of the chart of accounts.
+type SetBankAccountParentTxParams struct {
+	AccountID       int64
+	ParentAccountID *int64
+}
+
+// SetBankAccountParentTx re-parents AccountID under ParentAccountID,
+// validating that the new parent belongs to the same user, has the same
+// currency, and that the move doesn't create a cycle (the account can't
+// be its own ancestor), all inside a single locked transaction.
+func (store *SQLStore) SetBankAccountParentTx(ctx context.Context, arg SetBankAccountParentTxParams) (BankAccount, error) {
+	var result BankAccount
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetBankAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if arg.ParentAccountID == nil {
+			result, err = q.UpdateBankAccountParent(ctx, UpdateBankAccountParentParams{ID: account.ID})
+			return err
+		}
+
+		parent, err := q.GetBankAccount(ctx, *arg.ParentAccountID)
+		if err != nil {
+			return err
+		}
+		if parent.UserID != account.UserID {
+			return errCrossTenantParent
+		}
+		if parent.Currency != account.Currency {
+			return errCurrencyMismatch
+		}
+
+		for cur := parent; ; {
+			if cur.ID == account.ID {
+				return errParentCycle
+			}
+			if !cur.ParentAccountID.Valid {
+				break
+			}
+			cur, err = q.GetBankAccount(ctx, cur.ParentAccountID.Int64)
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err = q.UpdateBankAccountParent(ctx, UpdateBankAccountParentParams{
+			ID:              account.ID,
+			ParentAccountID: sql.NullInt64{Int64: *arg.ParentAccountID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
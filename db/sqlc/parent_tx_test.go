@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+)
+
+func newMockStore(t *testing.T) (Store, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewStore(conn), mock
+}
+
+// bankAccountRow builds a sqlmock row matching bankAccountCols, owned by
+// userID 1. parentID is the raw column value: nil for a root account, or
+// an int64 for a child.
+func bankAccountRow(id int64, parentID interface{}, currency string) *sqlmock.Rows {
+	return bankAccountRowForUser(id, 1, parentID, currency)
+}
+
+// bankAccountRowForUser is bankAccountRow with an explicit owner, for tests
+// that need accounts belonging to different users.
+func bankAccountRowForUser(id, userID int64, parentID interface{}, currency string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows(strColumnsOf(bankAccountCols)).
+		AddRow(id, userID, "acc-"+currency, "account", "0", currency, AccountTypeBank, parentID,
+			nil, nil, nil, nil, nil, nil, now, now)
+}
+
+func TestSQLStore_SetBankAccountParentTx_RejectsCurrencyMismatch(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM bank_accounts WHERE id = \\$1 FOR UPDATE").
+		WithArgs(int64(1)).
+		WillReturnRows(bankAccountRow(1, nil, "USD"))
+	mock.ExpectQuery("SELECT .* FROM bank_accounts WHERE id = \\$1\n").
+		WithArgs(int64(2)).
+		WillReturnRows(bankAccountRow(2, nil, "THB"))
+	mock.ExpectRollback()
+
+	parentID := int64(2)
+	_, err := store.SetBankAccountParentTx(context.Background(), SetBankAccountParentTxParams{
+		AccountID:       1,
+		ParentAccountID: &parentID,
+	})
+	if err != errCurrencyMismatch {
+		t.Fatalf("expected errCurrencyMismatch, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestSQLStore_SetBankAccountParentTx_RejectsCrossTenantParent(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM bank_accounts WHERE id = \\$1 FOR UPDATE").
+		WithArgs(int64(1)).
+		WillReturnRows(bankAccountRowForUser(1, 1, nil, "USD"))
+	// Account 2 belongs to a different user and must be rejected before the
+	// currency or cycle checks run.
+	mock.ExpectQuery("SELECT .* FROM bank_accounts WHERE id = \\$1\n").
+		WithArgs(int64(2)).
+		WillReturnRows(bankAccountRowForUser(2, 2, nil, "USD"))
+	mock.ExpectRollback()
+
+	parentID := int64(2)
+	_, err := store.SetBankAccountParentTx(context.Background(), SetBankAccountParentTxParams{
+		AccountID:       1,
+		ParentAccountID: &parentID,
+	})
+	if err != errCrossTenantParent {
+		t.Fatalf("expected errCrossTenantParent, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestSQLStore_SetBankAccountParentTx_RejectsCycle(t *testing.T) {
+	store, mock := newMockStore(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .* FROM bank_accounts WHERE id = \\$1 FOR UPDATE").
+		WithArgs(int64(1)).
+		WillReturnRows(bankAccountRow(1, nil, "USD"))
+	// Account 2 (the proposed new parent) is already a descendant of account 1.
+	mock.ExpectQuery("SELECT .* FROM bank_accounts WHERE id = \\$1\n").
+		WithArgs(int64(2)).
+		WillReturnRows(bankAccountRow(2, int64(1), "USD"))
+	mock.ExpectQuery("SELECT .* FROM bank_accounts WHERE id = \\$1\n").
+		WithArgs(int64(1)).
+		WillReturnRows(bankAccountRow(1, nil, "USD"))
+	mock.ExpectRollback()
+
+	parentID := int64(2)
+	_, err := store.SetBankAccountParentTx(context.Background(), SetBankAccountParentTxParams{
+		AccountID:       1,
+		ParentAccountID: &parentID,
+	})
+	if err != errParentCycle {
+		t.Fatalf("expected errParentCycle, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// strColumnsOf turns the package's "col1, col2, ..." constant into a
+// []string, so row fixtures stay in lockstep with bankAccountCols instead
+// of duplicating the column list.
+func strColumnsOf(cols string) []string {
+	var out []string
+	start := 0
+	for i := 0; i <= len(cols); i++ {
+		if i == len(cols) || cols[i] == ',' {
+			field := cols[start:i]
+			for len(field) > 0 && field[0] == ' ' {
+				field = field[1:]
+			}
+			out = append(out, field)
+			start = i + 1
+		}
+	}
+	return out
+}
@@ -0,0 +1,35 @@
+package db
+
+import "context"
+
+// Querier is implemented by Queries (sqlc-generated) and by MockStore
+// (mockgen-generated, see db/mock) so Server can depend on an interface
+// instead of a concrete *sql.DB.
+type Querier interface {
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	GetUser(ctx context.Context, id int64) (User, error)
+	GetUserByUsername(ctx context.Context, username string) (User, error)
+	ListUsers(ctx context.Context) ([]User, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	DeleteUser(ctx context.Context, id int64) error
+
+	CreateBankAccount(ctx context.Context, arg CreateBankAccountParams) (BankAccount, error)
+	GetBankAccount(ctx context.Context, id int64) (BankAccount, error)
+	GetBankAccountByAccountNumber(ctx context.Context, accountNumber string) (BankAccount, error)
+	GetBankAccountForUpdate(ctx context.Context, id int64) (BankAccount, error)
+	ListBankAccountsByUserID(ctx context.Context, userID int64) ([]BankAccount, error)
+	UpdateBankAccountBalance(ctx context.Context, arg UpdateBankAccountBalanceParams) (BankAccount, error)
+	UpdateBankAccountParent(ctx context.Context, arg UpdateBankAccountParentParams) (BankAccount, error)
+	UpdateBankAccountOFXConfig(ctx context.Context, arg UpdateBankAccountOFXConfigParams) (BankAccount, error)
+	DeleteBankAccount(ctx context.Context, id int64) error
+
+	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error)
+	ListTransactionsByBankAccountID(ctx context.Context, bankAccountID int64) ([]Transaction, error)
+
+	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+
+	ExistsSplit(ctx context.Context, accountID int64, remoteID string) (bool, error)
+	CreateSplit(ctx context.Context, arg CreateSplitParams) (Split, error)
+}
+
+var _ Querier = (*Queries)(nil)
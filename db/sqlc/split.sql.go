@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const existsSplit = `-- name: ExistsSplit :one
+SELECT EXISTS (SELECT 1 FROM splits WHERE account_id = $1 AND remote_id = $2)
+`
+
+// ExistsSplit reports whether an entry has already been imported for
+// accountID under this remote (e.g. OFX FITID) reference, so an import can
+// be re-run against the same statement without double-posting.
+func (q *Queries) ExistsSplit(ctx context.Context, accountID int64, remoteID string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, existsSplit, accountID, remoteID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const createSplit = `-- name: CreateSplit :one
+INSERT INTO splits (account_id, remote_id, entry_id, created_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, account_id, remote_id, entry_id, created_at
+`
+
+type CreateSplitParams struct {
+	AccountID int64  `json:"account_id"`
+	RemoteID  string `json:"remote_id"`
+	EntryID   int64  `json:"entry_id"`
+}
+
+func (q *Queries) CreateSplit(ctx context.Context, arg CreateSplitParams) (Split, error) {
+	row := q.db.QueryRowContext(ctx, createSplit, arg.AccountID, arg.RemoteID, arg.EntryID, time.Now())
+	var i Split
+	err := row.Scan(&i.ID, &i.AccountID, &i.RemoteID, &i.EntryID, &i.CreatedAt)
+	return i, err
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Store is everything a handler needs to talk to persistence: the plain
+// sqlc-generated queries (Querier) plus the multi-statement operations that
+// must run inside a single transaction.
+type Store interface {
+	Querier
+	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	DepositTx(ctx context.Context, arg DepositTxParams) (DepositTxResult, error)
+	WithdrawTx(ctx context.Context, arg WithdrawTxParams) (WithdrawTxResult, error)
+	SetBankAccountParentTx(ctx context.Context, arg SetBankAccountParentTxParams) (BankAccount, error)
+	ImportOFXTx(ctx context.Context, arg ImportOFXTxParams) (ImportOFXTxResult, error)
+}
+
+// SQLStore provides the Store implementation backed by a real *sql.DB.
+type SQLStore struct {
+	*Queries
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) Store {
+	return &SQLStore{
+		db:      db,
+		Queries: New(db),
+	}
+}
+
+// execTx runs fn inside a transaction, rolling back on error and committing
+// otherwise. fn receives a *Queries bound to the transaction so every call it
+// makes is part of the same atomic unit of work.
+func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := New(tx)
+	if err := fn(q); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx error: %v, rollback error: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
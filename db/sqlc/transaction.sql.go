@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/chawino/bank-api/money"
+)
+
+const createTransaction = `-- name: CreateTransaction :one
+INSERT INTO transactions (from_account_id, to_account_id, amount, kind, status, memo, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, from_account_id, to_account_id, amount, kind, status, memo, created_at
+`
+
+type CreateTransactionParams struct {
+	FromAccountID sql.NullInt64  `json:"from_account_id"`
+	ToAccountID   sql.NullInt64  `json:"to_account_id"`
+	Amount        money.Money    `json:"amount"`
+	Kind          string         `json:"kind"`
+	Status        string         `json:"status"`
+	Memo          sql.NullString `json:"memo"`
+	// CreatedAt backdates the transaction, e.g. to an OFX statement's
+	// DTPOSTED. Left zero, it defaults to time.Now().
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
+	createdAt := arg.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	row := q.db.QueryRowContext(ctx, createTransaction, arg.FromAccountID, arg.ToAccountID, arg.Amount, arg.Kind, arg.Status, arg.Memo, createdAt)
+	var i Transaction
+	err := row.Scan(&i.ID, &i.FromAccountID, &i.ToAccountID, &i.Amount, &i.Kind, &i.Status, &i.Memo, &i.CreatedAt)
+	return i, err
+}
+
+const listTransactionsByBankAccountID = `-- name: ListTransactionsByBankAccountID :many
+SELECT id, from_account_id, to_account_id, amount, kind, status, memo, created_at FROM transactions
+WHERE from_account_id = $1 OR to_account_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListTransactionsByBankAccountID(ctx context.Context, bankAccountID int64) ([]Transaction, error) {
+	rows, err := q.db.QueryContext(ctx, listTransactionsByBankAccountID, bankAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := []Transaction{}
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(&i.ID, &i.FromAccountID, &i.ToAccountID, &i.Amount, &i.Kind, &i.Status, &i.Memo, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/chawino/bank-api/money"
+)
+
+// TransferTxParams contains the input parameters of a transfer transaction.
+type TransferTxParams struct {
+	FromAccountID int64       `json:"from_account_id"`
+	ToAccountID   int64       `json:"to_account_id"`
+	Amount        money.Money `json:"amount"`
+}
+
+// TransferTxResult is the result of a transfer transaction.
+type TransferTxResult struct {
+	Transaction Transaction `json:"transaction"`
+	FromAccount BankAccount `json:"from_account"`
+	ToAccount   BankAccount `json:"to_account"`
+	FromEntry   Entry       `json:"from_entry"`
+	ToEntry     Entry       `json:"to_entry"`
+}
+
+// TransferTx moves Amount from FromAccountID to ToAccountID, recording the
+// movement as a Transaction with one Entry per side, all inside a single
+// transaction. Both accounts are locked with SELECT ... FOR UPDATE, always
+// in ascending account id order, so two transfers between the same pair of
+// accounts can never deadlock waiting on each other's locks. Accounts whose
+// currencies don't match are rejected rather than silently converted.
+func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		first, second := arg.FromAccountID, arg.ToAccountID
+		if second < first {
+			first, second = second, first
+		}
+		if _, err = q.GetBankAccountForUpdate(ctx, first); err != nil {
+			return err
+		}
+		if second != first {
+			if _, err = q.GetBankAccountForUpdate(ctx, second); err != nil {
+				return err
+			}
+		}
+
+		fromAccount, err := q.GetBankAccount(ctx, arg.FromAccountID)
+		if err != nil {
+			return err
+		}
+		toAccount, err := q.GetBankAccount(ctx, arg.ToAccountID)
+		if err != nil {
+			return err
+		}
+		if fromAccount.Currency != toAccount.Currency {
+			return errCurrencyMismatch
+		}
+		if fromAccount.Balance.Cmp(arg.Amount) < 0 {
+			return errBalanceLessThanAmount
+		}
+
+		result.FromAccount, err = q.UpdateBankAccountBalance(ctx, UpdateBankAccountBalanceParams{
+			ID:      fromAccount.ID,
+			Balance: fromAccount.Balance.Sub(arg.Amount),
+		})
+		if err != nil {
+			return err
+		}
+		result.ToAccount, err = q.UpdateBankAccountBalance(ctx, UpdateBankAccountBalanceParams{
+			ID:      toAccount.ID,
+			Balance: toAccount.Balance.Add(arg.Amount),
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transaction, err = q.CreateTransaction(ctx, CreateTransactionParams{
+			FromAccountID: sql.NullInt64{Int64: arg.FromAccountID, Valid: true},
+			ToAccountID:   sql.NullInt64{Int64: arg.ToAccountID, Valid: true},
+			Amount:        arg.Amount,
+			Kind:          TransactionKindTransfer,
+			Status:        TransactionStatusCompleted,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			TransactionID: result.Transaction.ID,
+			AccountID:     arg.FromAccountID,
+			Amount:        arg.Amount.Neg(),
+		})
+		if err != nil {
+			return err
+		}
+		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			TransactionID: result.Transaction.ID,
+			AccountID:     arg.ToAccountID,
+			Amount:        arg.Amount,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (username, hashed_password, first_name, last_name, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, username, hashed_password, first_name, last_name, created_at, updated_at
+`
+
+type CreateUserParams struct {
+	Username       string `json:"username"`
+	HashedPassword string `json:"hashed_password"`
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	now := time.Now()
+	row := q.db.QueryRowContext(ctx, createUser, arg.Username, arg.HashedPassword, arg.FirstName, arg.LastName, now, now)
+	var i User
+	err := row.Scan(&i.ID, &i.Username, &i.HashedPassword, &i.FirstName, &i.LastName, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const getUser = `-- name: GetUser :one
+SELECT id, username, hashed_password, first_name, last_name, created_at, updated_at FROM users WHERE id = $1
+`
+
+func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUser, id)
+	var i User
+	err := row.Scan(&i.ID, &i.Username, &i.HashedPassword, &i.FirstName, &i.LastName, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const getUserByUsername = `-- name: GetUserByUsername :one
+SELECT id, username, hashed_password, first_name, last_name, created_at, updated_at FROM users WHERE username = $1
+`
+
+func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByUsername, username)
+	var i User
+	err := row.Scan(&i.ID, &i.Username, &i.HashedPassword, &i.FirstName, &i.LastName, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT id, username, hashed_password, first_name, last_name, created_at, updated_at FROM users
+`
+
+func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(&i.ID, &i.Username, &i.HashedPassword, &i.FirstName, &i.LastName, &i.CreatedAt, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users SET first_name = $2, last_name = $3 WHERE id = $1
+RETURNING id, username, hashed_password, first_name, last_name, created_at, updated_at
+`
+
+type UpdateUserParams struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUser, arg.ID, arg.FirstName, arg.LastName)
+	var i User
+	err := row.Scan(&i.ID, &i.Username, &i.HashedPassword, &i.FirstName, &i.LastName, &i.CreatedAt, &i.UpdatedAt)
+	return i, err
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
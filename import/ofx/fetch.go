@@ -0,0 +1,166 @@
+package ofx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// fetchTimeout bounds how long Fetch will wait on the bank's OFX server.
+const fetchTimeout = 30 * time.Second
+
+// ErrUnsafeServerURL is returned when an OFX server URL fails the
+// scheme/destination checks in ValidateServerURL.
+var ErrUnsafeServerURL = errors.New("ofx: server URL must be https and point at a public host")
+
+// ValidateServerURL rejects anything that isn't a plain https:// URL
+// resolving to a public address. It's used as an early check when an
+// account's OFX config is saved and again at the start of Fetch, so obvious
+// misconfiguration is rejected without waiting on a dial. It is not the only
+// line of defense against SSRF: because its DNS lookup is a separate call
+// from the one the HTTP client performs at dial time, a server whose name
+// resolves to a public address here could be rebound to an internal one
+// before the dial happens. Fetch closes that gap by dialing through
+// safeDialContext, which re-resolves and validates the address it actually
+// connects to.
+func ValidateServerURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsafeServerURL, err)
+	}
+	if parsed.Scheme != "https" {
+		return fmt.Errorf("%w: scheme must be https", ErrUnsafeServerURL)
+	}
+
+	host := parsed.Hostname()
+	if host == "" {
+		return fmt.Errorf("%w: missing host", ErrUnsafeServerURL)
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsafeServerURL, err)
+	}
+	for _, ip := range ips {
+		if isDisallowedDestination(ip) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrUnsafeServerURL, host, ip)
+		}
+	}
+	return nil
+}
+
+func isDisallowedDestination(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified()
+}
+
+// safeDialContext is the DialContext used by Fetch's http.Client. It
+// resolves addr's host itself, rejects disallowed destinations, and dials
+// the validated IP directly, so the address checked and the address
+// connected to are always the same one — unlike ValidateServerURL, whose
+// result a DNS-rebinding attacker could invalidate between the check and
+// the HTTP client's own, independent resolution at dial time.
+func safeDialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var dialer net.Dialer
+	var lastErr error
+	for _, ip := range ips {
+		if isDisallowedDestination(ip.IP) {
+			lastErr = fmt.Errorf("%w: %s resolves to %s", ErrUnsafeServerURL, host, ip.IP)
+			continue
+		}
+		conn, dialErr := dialer.DialContext(ctx, network, net.JoinHostPort(ip.IP.String(), port))
+		if dialErr == nil {
+			return conn, nil
+		}
+		lastErr = dialErr
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("%w: no addresses found for %s", ErrUnsafeServerURL, host)
+	}
+	return nil, lastErr
+}
+
+// ClientConfig holds the connection details a bank's OFX server needs to
+// authorize a statement request: server URL plus the FI (org/fid), account
+// (bank id/account id) and user credentials identifying which statement to
+// pull.
+type ClientConfig struct {
+	URL      string
+	Org      string
+	FID      string
+	User     string
+	Password string
+	BankID   string
+	AcctID   string
+}
+
+// Fetch performs the OFX HTTP dialog (a signon request bundled with a bank
+// statement request in a single SGML document) against cfg.URL and returns
+// the transactions in the response. When httpClient is nil, Fetch builds
+// one that dials through safeDialContext, so the SSRF protection in
+// ValidateServerURL still holds even if cfg.URL's host is rebound to an
+// internal address between validation and the dial.
+func Fetch(ctx context.Context, httpClient *http.Client, cfg ClientConfig) ([]Transaction, error) {
+	if err := ValidateServerURL(cfg.URL); err != nil {
+		return nil, err
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout:   fetchTimeout,
+			Transport: &http.Transport{DialContext: safeDialContext},
+		}
+	}
+
+	now := time.Now().UTC().Format(dtPostedLayout)
+	body := buildStatementRequest(cfg, now)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-ofx")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ofx: server returned status %d", resp.StatusCode)
+	}
+
+	return Parse(resp.Body)
+}
+
+// buildStatementRequest assembles the minimal SGML body a signon + bank
+// statement download request needs. One-day precision is fine since DTSTART
+// is only used by the bank to bound how far back it searches.
+func buildStatementRequest(cfg ClientConfig, dtClient string) string {
+	var b strings.Builder
+	b.WriteString("OFXHEADER:100\r\nDATA:OFXSGML\r\nVERSION:102\r\nSECURITY:NONE\r\nENCODING:USASCII\r\nCHARSET:1252\r\nCOMPRESSION:NONE\r\nOLDFILEUID:NONE\r\nNEWFILEUID:NONE\r\n\r\n")
+	fmt.Fprintf(&b, "<OFX><SIGNONMSGSRQV1><SONRQ><DTCLIENT>%s<USERID>%s<USERPASS>%s<LANGUAGE>ENG<FI><ORG>%s<FID>%s</FI><APPID>QWIN<APPVER>2700</SONRQ></SIGNONMSGSRQV1>",
+		dtClient, cfg.User, cfg.Password, cfg.Org, cfg.FID)
+	fmt.Fprintf(&b, "<BANKMSGSRQV1><STMTTRNRQ><TRNUID>%s<STMTRQ><BANKACCTFROM><BANKID>%s<ACCTID>%s<ACCTTYPE>CHECKING</BANKACCTFROM><INCTRAN><DTSTART>%s<INCLUDE>Y</INCTRAN></STMTRQ></STMTTRNRQ></BANKMSGSRQV1></OFX>",
+		dtClient, cfg.BankID, cfg.AcctID, dtClient[:len("20060102")])
+	return b.String()
+}
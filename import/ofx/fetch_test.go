@@ -0,0 +1,21 @@
+package ofx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestSafeDialContext_RejectsLoopback(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	defer ln.Close()
+
+	_, err = safeDialContext(context.Background(), "tcp", ln.Addr().String())
+	if !errors.Is(err, ErrUnsafeServerURL) {
+		t.Fatalf("expected ErrUnsafeServerURL, got %v", err)
+	}
+}
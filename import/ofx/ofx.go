@@ -0,0 +1,98 @@
+// Package ofx parses OFX/QFX bank statement downloads into a flat list of
+// transactions ready to merge into a ledger account.
+//
+// OFX is SGML, not XML: aggregate elements like <STMTTRN> are reliably
+// closed, but leaf elements (<FITID>, <TRNAMT>, ...) often aren't. A full
+// SGML parser isn't worth it here, so Parse scans each <STMTTRN>...</STMTTRN>
+// block with a small set of per-tag regexes instead of a general grammar.
+package ofx
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chawino/bank-api/money"
+)
+
+// Transaction is a single statement line extracted from an OFX <STMTTRN>.
+type Transaction struct {
+	FITID  string
+	Posted time.Time
+	Amount money.Money
+	Name   string
+	Memo   string
+}
+
+var stmtTrnRe = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+
+func tagRe(tag string) *regexp.Regexp {
+	return regexp.MustCompile(`(?is)<` + tag + `>\s*([^<\r\n]*)`)
+}
+
+var (
+	fitIDRe    = tagRe("FITID")
+	dtPostedRe = tagRe("DTPOSTED")
+	trnAmtRe   = tagRe("TRNAMT")
+	nameRe     = tagRe("NAME")
+	memoRe     = tagRe("MEMO")
+)
+
+// dtPostedLayout matches OFX's "YYYYMMDDHHMMSS[.xxx[:TZ]]" DTPOSTED format,
+// truncated to the date+time portion every variant shares.
+const dtPostedLayout = "20060102150405"
+
+// Parse reads r as an OFX/QFX document and returns every <STMTTRN> it finds,
+// in document order.
+func Parse(r io.Reader) ([]Transaction, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []Transaction
+	for _, block := range stmtTrnRe.FindAllStringSubmatch(string(body), -1) {
+		txn, err := parseBlock(block[1])
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, txn)
+	}
+	return transactions, nil
+}
+
+func parseBlock(block string) (Transaction, error) {
+	fitID := strings.TrimSpace(firstMatch(fitIDRe, block))
+	if fitID == "" {
+		return Transaction{}, fmt.Errorf("ofx: STMTTRN block missing FITID")
+	}
+
+	amountStr := strings.TrimSpace(firstMatch(trnAmtRe, block))
+	amount, err := money.Parse(amountStr)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("ofx: FITID %s: %w", fitID, err)
+	}
+
+	var posted time.Time
+	if dtPosted := strings.TrimSpace(firstMatch(dtPostedRe, block)); len(dtPosted) >= len(dtPostedLayout) {
+		posted, _ = time.Parse(dtPostedLayout, dtPosted[:len(dtPostedLayout)])
+	}
+
+	return Transaction{
+		FITID:  fitID,
+		Posted: posted,
+		Amount: amount,
+		Name:   strings.TrimSpace(firstMatch(nameRe, block)),
+		Memo:   strings.TrimSpace(firstMatch(memoRe, block)),
+	}, nil
+}
+
+func firstMatch(re *regexp.Regexp, s string) string {
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
@@ -0,0 +1,76 @@
+package ofx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleOFX = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:USASCII
+CHARSET:1252
+COMPRESSION:NONE
+OLDFILEUID:NONE
+NEWFILEUID:NONE
+
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240115120000
+<TRNAMT>-42.50
+<FITID>FITID-1
+<NAME>COFFEE SHOP
+<MEMO>latte
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240116093000
+<TRNAMT>1000.00
+<FITID>FITID-2
+<NAME>PAYROLL
+</STMTTRN>
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>
+`
+
+func TestParse_ExtractsTransactionsInOrder(t *testing.T) {
+	transactions, err := Parse(strings.NewReader(sampleOFX))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(transactions))
+	}
+
+	first := transactions[0]
+	if first.FITID != "FITID-1" {
+		t.Fatalf("expected FITID-1, got %q", first.FITID)
+	}
+	if first.Amount.String() != "-42.5000" {
+		t.Fatalf("expected amount -42.5000, got %s", first.Amount.String())
+	}
+	if first.Name != "COFFEE SHOP" || first.Memo != "latte" {
+		t.Fatalf("unexpected name/memo: %q / %q", first.Name, first.Memo)
+	}
+	wantPosted := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	if !first.Posted.Equal(wantPosted) {
+		t.Fatalf("expected posted %v, got %v", wantPosted, first.Posted)
+	}
+
+	second := transactions[1]
+	if second.FITID != "FITID-2" {
+		t.Fatalf("expected FITID-2, got %q", second.FITID)
+	}
+	if second.Amount.String() != "1000.0000" {
+		t.Fatalf("expected amount 1000.0000, got %s", second.Amount.String())
+	}
+}
+
+func TestParse_RejectsSTMTTRNMissingFITID(t *testing.T) {
+	body := `<STMTTRN><TRNAMT>10.00<NAME>NO FITID</STMTTRN>`
+	if _, err := Parse(strings.NewReader(body)); err == nil {
+		t.Fatal("expected an error for a STMTTRN block missing FITID")
+	}
+}
@@ -0,0 +1,24 @@
+package money
+
+import "testing"
+
+func TestValidCurrency(t *testing.T) {
+	cases := []struct {
+		code string
+		want bool
+	}{
+		{"USD", true},
+		{"THB", true},
+		{"EUR", true},
+		{"ZZZ", false},
+		{"XXX", false},
+		{"US", false},
+		{"usd", false},
+	}
+
+	for _, c := range cases {
+		if got := ValidCurrency(c.code); got != c.want {
+			t.Errorf("ValidCurrency(%q) = %v, want %v", c.code, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Package money represents account balances and transaction amounts as
+// exact decimal values instead of integers or floats, so no precision is
+// lost rounding to/from a minor unit (cents) and fractional-currency
+// amounts aren't silently truncated.
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Scale is the number of decimal places amounts are stored and printed
+// with, matching the NUMERIC(20,4) columns backing balances and amounts.
+const Scale = 4
+
+// Money is an exact decimal amount, backed by a rational number so
+// addition and subtraction never accumulate floating-point error.
+type Money struct {
+	rat *big.Rat
+}
+
+// Zero is the additive identity, 0.0000.
+func Zero() Money {
+	return Money{rat: new(big.Rat)}
+}
+
+// Parse reads a decimal string such as "12.50" or "-3" into a Money.
+func Parse(s string) (Money, error) {
+	rat, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return Money{}, fmt.Errorf("money: invalid amount %q", s)
+	}
+	return Money{rat: rat}, nil
+}
+
+// MustParse is like Parse but panics on error. It exists for tests and
+// other call sites with a compile-time-constant amount.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (m Money) rational() *big.Rat {
+	if m.rat == nil {
+		return new(big.Rat)
+	}
+	return m.rat
+}
+
+// Add returns m + other.
+func (m Money) Add(other Money) Money {
+	return Money{rat: new(big.Rat).Add(m.rational(), other.rational())}
+}
+
+// Sub returns m - other.
+func (m Money) Sub(other Money) Money {
+	return Money{rat: new(big.Rat).Sub(m.rational(), other.rational())}
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return Money{rat: new(big.Rat).Neg(m.rational())}
+}
+
+// Cmp returns -1, 0 or +1 as m is less than, equal to, or greater than other.
+func (m Money) Cmp(other Money) int {
+	return m.rational().Cmp(other.rational())
+}
+
+// IsNegative reports whether m is less than zero.
+func (m Money) IsNegative() bool {
+	return m.rational().Sign() < 0
+}
+
+// IsPositive reports whether m is greater than zero.
+func (m Money) IsPositive() bool {
+	return m.rational().Sign() > 0
+}
+
+// String formats m as a fixed-scale decimal, e.g. "12.5000".
+func (m Money) String() string {
+	return m.rational().FloatString(Scale)
+}
+
+// MarshalJSON encodes m as a JSON string so clients never have to worry
+// about a JSON number silently losing precision.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+// UnmarshalJSON decodes m from a JSON string or (for convenience) a bare
+// JSON number.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner so a Money can be read directly out of a
+// NUMERIC column.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = Zero()
+		return nil
+	case []byte:
+		parsed, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T into Money", src)
+	}
+}
+
+// Value implements driver.Valuer so a Money is written to a NUMERIC column
+// as a plain decimal string.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
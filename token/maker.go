@@ -0,0 +1,10 @@
+package token
+
+import "time"
+
+// Maker is implemented by JWTMaker and PasetoMaker so the auth middleware
+// and login handler don't need to know which signing scheme is configured.
+type Maker interface {
+	CreateToken(userID int64, duration time.Duration) (string, *Payload, error)
+	VerifyToken(token string) (*Payload, error)
+}
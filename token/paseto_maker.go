@@ -0,0 +1,87 @@
+package token
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// pasetoVersion is a v2.local-style token: XChaCha20-Poly1305 AEAD sealing a
+// JSON payload, formatted as "v2.local.<base64>" like a real PASETO token.
+const pasetoVersion = "v2.local."
+
+// PasetoMaker creates/verifies symmetrically-encrypted access tokens.
+type PasetoMaker struct {
+	aead cipher.AEAD
+}
+
+// NewPasetoMaker builds a PasetoMaker. symmetricKey must be exactly
+// chacha20poly1305.KeySize bytes.
+func NewPasetoMaker(symmetricKey string) (*PasetoMaker, error) {
+	if len(symmetricKey) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("invalid key size: must be exactly %d characters", chacha20poly1305.KeySize)
+	}
+
+	aead, err := chacha20poly1305.NewX([]byte(symmetricKey))
+	if err != nil {
+		return nil, err
+	}
+	return &PasetoMaker{aead: aead}, nil
+}
+
+func (maker *PasetoMaker) CreateToken(userID int64, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(userID, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", payload, err
+	}
+
+	nonce := make([]byte, maker.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", payload, err
+	}
+
+	sealed := maker.aead.Seal(nonce, nonce, body, nil)
+	return pasetoVersion + base64.RawURLEncoding.EncodeToString(sealed), payload, nil
+}
+
+func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+	if !strings.HasPrefix(token, pasetoVersion) {
+		return nil, ErrInvalidToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, pasetoVersion))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	nonceSize := maker.aead.NonceSize()
+	if len(raw) < nonceSize {
+		return nil, ErrInvalidToken
+	}
+	nonce, sealed := raw[:nonceSize], raw[nonceSize:]
+
+	body, err := maker.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if err := payload.Valid(); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
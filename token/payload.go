@@ -0,0 +1,47 @@
+package token
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrExpiredToken = errors.New("token has expired")
+	ErrInvalidToken = errors.New("token is invalid")
+)
+
+// Payload is the data embedded inside an access token, common to both the
+// JWT and PASETO makers.
+type Payload struct {
+	ID        uuid.UUID `json:"jti"`
+	UserID    int64     `json:"user_id"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiredAt time.Time `json:"expires_at"`
+}
+
+// NewPayload creates a new token payload for a given user and duration.
+func NewPayload(userID int64, duration time.Duration) (*Payload, error) {
+	tokenID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Payload{
+		ID:        tokenID,
+		UserID:    userID,
+		IssuedAt:  now,
+		ExpiredAt: now.Add(duration),
+	}, nil
+}
+
+// Valid satisfies jwt.Claims so *Payload can be used directly as the claims
+// type when signing/parsing a JWT.
+func (payload *Payload) Valid() error {
+	if time.Now().After(payload.ExpiredAt) {
+		return ErrExpiredToken
+	}
+	return nil
+}
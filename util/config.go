@@ -0,0 +1,52 @@
+package util
+
+import (
+	"os"
+	"time"
+)
+
+const (
+	TokenMakerJWT    = "jwt"
+	TokenMakerPaseto = "paseto"
+)
+
+// Config is read from the environment, mirroring the rest of the app's
+// os.Getenv-based configuration.
+type Config struct {
+	DatabaseURL         string
+	Port                string
+	TokenMaker          string
+	TokenSymmetricKey   string
+	AccessTokenDuration time.Duration
+	LogLevel            string
+}
+
+// LoadConfig reads Config from the environment. TOKEN_MAKER, LOG_LEVEL and
+// ACCESS_TOKEN_DURATION fall back to defaults (jwt, info, 15m) when unset,
+// and ACCESS_TOKEN_DURATION also when unparsable; DATABASE_URL, PORT and
+// TOKEN_SYMMETRIC_KEY have no default and are read as-is.
+func LoadConfig() Config {
+	tokenMaker := os.Getenv("TOKEN_MAKER")
+	if tokenMaker == "" {
+		tokenMaker = TokenMakerJWT
+	}
+
+	duration, err := time.ParseDuration(os.Getenv("ACCESS_TOKEN_DURATION"))
+	if err != nil {
+		duration = 15 * time.Minute
+	}
+
+	logLevel := os.Getenv("LOG_LEVEL")
+	if logLevel == "" {
+		logLevel = "info"
+	}
+
+	return Config{
+		DatabaseURL:         os.Getenv("DATABASE_URL"),
+		Port:                os.Getenv("PORT"),
+		TokenMaker:          tokenMaker,
+		TokenSymmetricKey:   os.Getenv("TOKEN_SYMMETRIC_KEY"),
+		AccessTokenDuration: duration,
+		LogLevel:            logLevel,
+	}
+}